@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/baseline"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	baselineFlag            = "baseline"
+	failOnStaleBaselineFlag = "fail-on-stale-baseline"
+	defaultBaselinePath     = ".pint-baseline.json"
+)
+
+func baselineFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.PathFlag{
+			Name:  baselineFlag,
+			Usage: "Baseline file with problems that should not be reported",
+		},
+		&cli.BoolFlag{
+			Name:  failOnStaleBaselineFlag,
+			Usage: "Fail if baseline file contains problems that are no longer reported",
+		},
+	}
+}
+
+func actionBaseline(c *cli.Context) (err error) {
+	err = initLogger(c.String(logLevelFlag))
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %s", err)
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	cfg, err := config.Load(c.Path(configFlag))
+	if err != nil {
+		return fmt.Errorf("failed to load config file %q: %s", c.Path(configFlag), err)
+	}
+
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+
+	d := discovery.NewGlobFileFinder()
+	toScan, err := d.Find(paths...)
+	if err != nil {
+		return err
+	}
+
+	if len(toScan.Paths()) == 0 {
+		return fmt.Errorf("no matching files")
+	}
+
+	summary := scanFiles(cfg, toScan, &discovery.NoopLineFinder{})
+
+	b := baseline.New(summary.Reports)
+	path := c.Path(baselineFlag)
+	if err = b.Save(path); err != nil {
+		return fmt.Errorf("failed to write baseline file %q: %w", path, err)
+	}
+	log.Info().Str("path", path).Int("entries", len(b.Entries)).Int("problems", len(summary.Reports)).Msg("Baseline file saved")
+
+	return nil
+}
+
+// applyBaseline removes all reports matching entries in the baseline file
+// passed via --baseline flag, it returns all baseline entries that didn't
+// match any report and for which inScope returns true
+func applyBaseline(c *cli.Context, summary *reporter.Summary, inScope func(baseline.Entry) bool) (stale []baseline.Entry, err error) {
+	path := c.Path(baselineFlag)
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := baseline.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline file %q: %w", path, err)
+	}
+
+	before := len(summary.Reports)
+	var unmatched []baseline.Entry
+	summary.Reports, unmatched = b.Filter(summary.Reports)
+	log.Info().Str("path", path).Int("hidden", before-len(summary.Reports)).Msg("Baseline file loaded")
+
+	for _, e := range unmatched {
+		if inScope(e) {
+			stale = append(stale, e)
+		}
+	}
+	return stale, nil
+}
+
+// checkStaleBaseline logs all stale baseline entries, it will return an error
+// if there are any and --fail-on-stale-baseline flag was passed
+func checkStaleBaseline(c *cli.Context, stale []baseline.Entry) error {
+	if len(stale) == 0 {
+		return nil
+	}
+
+	if !c.Bool(failOnStaleBaselineFlag) {
+		log.Info().Int("entries", len(stale)).Msg("Baseline file contains problems that are no longer reported")
+		return nil
+	}
+
+	for _, e := range stale {
+		log.Warn().
+			Str("path", e.Path).
+			Str("name", e.Name).
+			Str("reporter", e.Reporter).
+			Str("text", e.Text).
+			Msg("Stale baseline entry")
+	}
+	return fmt.Errorf("baseline file contains %d stale entries", len(stale))
+}
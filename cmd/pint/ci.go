@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"time"
 
+	"github.com/cloudflare/pint/internal/baseline"
+	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/config"
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/git"
@@ -26,6 +29,22 @@ func actionCI(c *cli.Context) (err error) {
 		return fmt.Errorf("failed to load config file %q: %s", c.Path(configFlag), err)
 	}
 
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+
+	// only modified files are scanned, so most recording rules used by those
+	// will be defined in files we don't know about
+	cfg.SetDisabledChecks([]string{checks.DependencyCheckName})
+
+	reps, closeOutput, err := newOutputReporters(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := closeOutput(); err == nil {
+			err = cerr
+		}
+	}()
+
 	includeRe := []*regexp.Regexp{}
 	for _, pattern := range cfg.CI.Include {
 		includeRe = append(includeRe, regexp.MustCompile("^"+pattern+"$"))
@@ -48,8 +67,25 @@ func actionCI(c *cli.Context) (err error) {
 	gitBlame := discovery.NewGitBlameLineFinder(git.RunGit, toScan.Commits())
 	summary := scanFiles(cfg, toScan, gitBlame)
 
-	reps := []reporter.Reporter{
-		reporter.NewConsoleReporter(os.Stderr),
+	if cfg.IsCheckEnabled(checks.RemovedCheckName) {
+		removed, err := checkRemovedRules(git.RunGit, cfg.CI.BaseBranch, includeRe)
+		if err != nil {
+			return err
+		}
+		summary.Reports = append(summary.Reports, removed...)
+	}
+
+	// only rules that were checked can be used to tell if baseline entry is stale
+	checked := map[string]struct{}{}
+	for _, entry := range summary.Entries {
+		checked[entry.Path+"\n"+entry.Rule.Name()] = struct{}{}
+	}
+	stale, err := applyBaseline(c, &summary, func(e baseline.Entry) bool {
+		_, ok := checked[e.Path+"\n"+e.Name]
+		return ok
+	})
+	if err != nil {
+		return err
 	}
 
 	if cfg.Repository != nil && cfg.Repository.BitBucket != nil {
@@ -70,6 +106,62 @@ func actionCI(c *cli.Context) (err error) {
 		reps = append(reps, br)
 	}
 
+	if cfg.Repository != nil && cfg.Repository.GitHub != nil {
+		token, ok := os.LookupEnv("GITHUB_AUTH_TOKEN")
+		if !ok {
+			return fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
+		}
+
+		prNum, err := githubPullRequestNumber()
+		if err != nil {
+			return err
+		}
+
+		timeout, _ := time.ParseDuration(cfg.Repository.GitHub.Timeout)
+		gr := reporter.NewGitHubReporter(
+			cfg.Repository.GitHub.BaseURI,
+			timeout,
+			token,
+			cfg.Repository.GitHub.Owner,
+			cfg.Repository.GitHub.Repo,
+			prNum,
+			git.RunGit,
+		)
+		reps = append(reps, gr)
+	}
+
+	if cfg.Repository != nil && cfg.Repository.GitLab != nil {
+		var token string
+		var mrIID int
+		if cfg.Repository.GitLab.URI != "" {
+			var ok bool
+			token, ok = os.LookupEnv("GITLAB_AUTH_TOKEN")
+			if !ok {
+				return fmt.Errorf("GITLAB_AUTH_TOKEN env variable is required when reporting to GitLab")
+			}
+
+			v, ok := os.LookupEnv("CI_MERGE_REQUEST_IID")
+			if !ok {
+				return fmt.Errorf("CI_MERGE_REQUEST_IID env variable is required when reporting to GitLab")
+			}
+			if mrIID, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("CI_MERGE_REQUEST_IID env variable must be a number: %s", err)
+			}
+		}
+
+		timeout, _ := time.ParseDuration(cfg.Repository.GitLab.Timeout)
+		gr := reporter.NewGitLabReporter(
+			cfg.Repository.GitLab.CodeQuality,
+			cfg.Repository.GitLab.URI,
+			timeout,
+			token,
+			cfg.Repository.GitLab.Project,
+			mrIID,
+			git.RunGit,
+		)
+		reps = append(reps, gr)
+	}
+
 	bySeverity := map[string]interface{}{}
 	for s, c := range summary.CountBySeverity() {
 		bySeverity[s.String()] = c
@@ -78,5 +170,29 @@ func actionCI(c *cli.Context) (err error) {
 		log.Info().Fields(bySeverity).Msg("Problems found")
 	}
 
-	return submitReports(reps, summary)
+	if err = submitReports(reps, summary); err != nil {
+		return err
+	}
+
+	return checkStaleBaseline(c, stale)
+}
+
+var githubRefRe = regexp.MustCompile("^refs/pull/([0-9]+)/merge$")
+
+// githubPullRequestNumber returns the number of pull request we're running for,
+// either from GITHUB_PULL_REQUEST_NUMBER or GITHUB_REF set by GitHub Actions
+func githubPullRequestNumber() (int, error) {
+	if v, ok := os.LookupEnv("GITHUB_PULL_REQUEST_NUMBER"); ok {
+		prNum, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable must be a number: %s", err)
+		}
+		return prNum, nil
+	}
+
+	if parts := githubRefRe.FindStringSubmatch(os.Getenv("GITHUB_REF")); len(parts) == 2 {
+		return strconv.Atoi(parts[1])
+	}
+
+	return 0, fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/fixer"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/pkg/diff"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	dryRunFlag = "dry-run"
+)
+
+func actionFix(c *cli.Context) (err error) {
+	err = initLogger(c.String(logLevelFlag))
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %s", err)
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	cfg, err := config.Load(c.Path(configFlag))
+	if err != nil {
+		return fmt.Errorf("failed to load config file %q: %s", c.Path(configFlag), err)
+	}
+
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+
+	d := discovery.NewGlobFileFinder()
+	toScan, err := d.Find(paths...)
+	if err != nil {
+		return err
+	}
+
+	if len(toScan.Paths()) == 0 {
+		return fmt.Errorf("no matching files")
+	}
+
+	summary := scanFiles(cfg, toScan, &discovery.NoopLineFinder{})
+
+	fixes := fixesPerFile(summary.Reports)
+	files := make([]string, 0, len(fixes))
+	for path := range fixes {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+
+	var applied, skipped int
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		result := fixer.Apply(content, fixes[path])
+		applied += result.Applied
+		skipped += result.Skipped
+		log.Info().Str("path", path).Int("applied", result.Applied).Int("skipped", result.Skipped).Msg("Fixes processed")
+		if result.Applied == 0 {
+			continue
+		}
+
+		if c.Bool(dryRunFlag) {
+			if err = diff.Text("a/"+path, "b/"+path, content, result.Content, os.Stdout); err != nil {
+				return fmt.Errorf("failed to generate diff for %s: %w", path, err)
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(path, result.Content, info.Mode()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if skipped > 0 {
+		log.Info().Int("applied", applied).Int("skipped", skipped).Msg("Some fixes were skipped, run pint fix again to apply them")
+	}
+
+	return nil
+}
+
+// fixesPerFile returns all suggested fixes grouped by file path, fixes are
+// ordered by their position in the file so results are always the same
+func fixesPerFile(reports []reporter.Report) map[string][]checks.Fix {
+	reps := make([]reporter.Report, 0, len(reports))
+	for _, report := range reports {
+		if report.Problem.Fix != nil {
+			reps = append(reps, report)
+		}
+	}
+	sort.SliceStable(reps, func(i, j int) bool {
+		if reps[i].Path != reps[j].Path {
+			return reps[i].Path < reps[j].Path
+		}
+		if reps[i].Problem.Lines[0] != reps[j].Problem.Lines[0] {
+			return reps[i].Problem.Lines[0] < reps[j].Problem.Lines[0]
+		}
+		if reps[i].Problem.Reporter != reps[j].Problem.Reporter {
+			return reps[i].Problem.Reporter < reps[j].Problem.Reporter
+		}
+		return reps[i].Problem.Text < reps[j].Problem.Text
+	})
+
+	fixes := map[string][]checks.Fix{}
+	for _, report := range reps {
+		fixes[report.Path] = append(fixes[report.Path], *report.Problem.Fix)
+	}
+	return fixes
+}
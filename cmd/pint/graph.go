@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	graphFormatFlag = "format"
+)
+
+func actionGraph(c *cli.Context) (err error) {
+	err = initLogger(c.String(logLevelFlag))
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %s", err)
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	format := c.String(graphFormatFlag)
+	if format != "dot" && format != "json" {
+		return fmt.Errorf("unsupported graph format %q, must be one of: dot, json", format)
+	}
+
+	d := discovery.NewGlobFileFinder()
+	toScan, err := d.Find(paths...)
+	if err != nil {
+		return err
+	}
+
+	if len(toScan.Paths()) == 0 {
+		return fmt.Errorf("no matching files")
+	}
+
+	entries := []graph.Entry{}
+	p := parser.NewParser()
+	for _, path := range toScan.Paths() {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %q: %s", path, err)
+		}
+		content, err := parser.ReadContent(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %s", path, err)
+		}
+		rules, err := p.Parse(content)
+		if err != nil {
+			log.Error().Str("path", path).Err(err).Msg("Failed to parse file content")
+			continue
+		}
+		log.Info().Str("path", path).Int("rules", len(rules)).Msg("File parsed")
+		for _, rule := range rules {
+			entries = append(entries, graph.Entry{Path: path, Rule: rule})
+		}
+	}
+
+	g := graph.New(entries)
+	if format == "json" {
+		return g.WriteJSON(os.Stdout)
+	}
+	return g.WriteDOT(os.Stdout)
+}
@@ -2,12 +2,11 @@ package main
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/cloudflare/pint/internal/baseline"
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/config"
 	"github.com/cloudflare/pint/internal/discovery"
-	"github.com/cloudflare/pint/internal/reporter"
 
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
@@ -31,6 +30,16 @@ func actionLint(c *cli.Context) (err error) {
 
 	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
 
+	reps, closeOutput, err := newOutputReporters(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := closeOutput(); err == nil {
+			err = cerr
+		}
+	}()
+
 	d := discovery.NewGlobFileFinder()
 	toScan, err := d.Find(paths...)
 	if err != nil {
@@ -43,12 +52,27 @@ func actionLint(c *cli.Context) (err error) {
 
 	summary := scanFiles(cfg, toScan, &discovery.NoopLineFinder{})
 
-	r := reporter.NewConsoleReporter(os.Stderr)
-	err = r.Submit(summary)
+	scanned := map[string]struct{}{}
+	for _, path := range toScan.Paths() {
+		scanned[path] = struct{}{}
+	}
+	stale, err := applyBaseline(c, &summary, func(e baseline.Entry) bool {
+		_, ok := scanned[e.Path]
+		return ok
+	})
 	if err != nil {
 		return err
 	}
 
+	err = submitReports(reps, summary)
+	if err != nil {
+		return err
+	}
+
+	if err = checkStaleBaseline(c, stale); err != nil {
+		return err
+	}
+
 	bySeverity := map[string]interface{}{}
 	var problems int
 	for s, c := range summary.CountBySeverity() {
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/lsp"
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	onlineFlag   = "online"
+	debounceFlag = "debounce"
+)
+
+func actionLSP(c *cli.Context) (err error) {
+	err = initLogger(c.String(logLevelFlag))
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %s", err)
+	}
+
+	cfg, err := config.Load(c.Path(configFlag))
+	if err != nil {
+		return fmt.Errorf("failed to load config file %q: %s", c.Path(configFlag), err)
+	}
+
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+
+	debounce := c.Duration(debounceFlag)
+	if !c.Bool(onlineFlag) {
+		// without any Prometheus servers only offline checks are enabled
+		cfg.Prometheus = nil
+		debounce = 0
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	ws := newLSPWorkspace(cwd, c.Args().Slice())
+
+	log.Info().Bool("online", c.Bool(onlineFlag)).Strs("workspace", ws.paths).Msg("Starting language server")
+	srv := lsp.NewServer(func(path string, content []byte) []checks.Problem {
+		path = relativePath(cwd, path)
+		return lintContent(cfg, path, content, ws.entriesExcept(path))
+	}, ws.refresh, debounce)
+	return srv.Serve(os.Stdin, os.Stdout)
+}
+
+// lspWorkspace caches rules parsed from all workspace files, those are only
+// parsed again after files on disk might have changed, documents that are
+// being edited are parsed on every change and replace their cached rules
+type lspWorkspace struct {
+	cwd   string
+	paths []string
+
+	lock    sync.Mutex
+	entries []graph.Entry
+	loaded  bool
+}
+
+func newLSPWorkspace(cwd string, paths []string) *lspWorkspace {
+	return &lspWorkspace{cwd: cwd, paths: paths}
+}
+
+// refresh marks cached rules as stale, so they are parsed again next time
+// they are needed
+func (ws *lspWorkspace) refresh() {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	ws.loaded = false
+}
+
+// entriesExcept returns cached rules from all workspace files other than
+// given path
+func (ws *lspWorkspace) entriesExcept(path string) (entries []graph.Entry) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	if !ws.loaded {
+		ws.entries = workspaceEntries(ws.cwd, ws.paths)
+		ws.loaded = true
+	}
+
+	for _, e := range ws.entries {
+		if e.Path != path {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// relativePath returns path relative to the working directory, since config
+// rules match on those, paths outside of it are returned unchanged
+func relativePath(cwd, path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// workspaceEntries parses all rule files matching given paths, so checks using
+// all scanned rules can see rules defined in other files
+func workspaceEntries(cwd string, paths []string) (entries []graph.Entry) {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	d := discovery.NewGlobFileFinder()
+	toScan, err := d.Find(paths...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find workspace files")
+		return nil
+	}
+	log.Debug().Int("files", len(toScan.Paths())).Msg("Parsing workspace files")
+
+	p := parser.NewParser()
+	for _, name := range toScan.Paths() {
+		path := relativePath(cwd, name)
+		f, err := os.Open(name)
+		if err != nil {
+			log.Error().Str("path", path).Err(err).Msg("Failed to open file for reading")
+			continue
+		}
+		content, err := parser.ReadContent(f)
+		f.Close()
+		if err != nil {
+			log.Error().Str("path", path).Err(err).Msg("Failed to read file content")
+			continue
+		}
+		rules, err := p.Parse(content)
+		if err != nil {
+			log.Debug().Str("path", path).Err(err).Msg("Failed to parse file content")
+			continue
+		}
+		for _, rule := range rules {
+			entries = append(entries, graph.Entry{Path: path, Rule: rule})
+		}
+	}
+	return entries
+}
+
+// lintContent runs all checks on given file content, it's used for files
+// that are being edited and so might not be saved to disk, rules from other
+// workspace files are only used by checks that need to know about all rules
+func lintContent(cfg config.Config, path string, data []byte, workspace []graph.Entry) (problems []checks.Problem) {
+	content, err := parser.ReadContent(bytes.NewReader(data))
+	if err != nil {
+		return []checks.Problem{scanProblem(path, err).Problem}
+	}
+
+	p := parser.NewParser()
+	rules, groups, err := p.ParseWithGroups(content)
+	if err != nil {
+		return []checks.Problem{scanProblem(path, err).Problem}
+	}
+
+	entries := workspace
+	for _, rule := range rules {
+		entries = append(entries, graph.Entry{Path: path, Rule: rule})
+	}
+	g := graph.New(entries)
+
+	problems = append(problems, checkFile(cfg, path, content, groups)...)
+	for _, rule := range rules {
+		if rule.Error.Err != nil {
+			problems = append(problems, ruleErrorProblem(rule))
+			continue
+		}
+		for _, check := range cfg.GetChecksForRule(path, rule) {
+			problems = append(problems, check.Check(rule)...)
+		}
+		for _, check := range cfg.GetChecksForRuleSet(path, rule, g) {
+			problems = append(problems, check.Check(rule)...)
+		}
+	}
+	log.Debug().Str("path", path).Int("rules", len(rules)).Int("problems", len(problems)).Msg("File checked")
+
+	return problems
+}
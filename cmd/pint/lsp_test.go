@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintContentWorkspace(t *testing.T) {
+	dir := t.TempDir()
+
+	cfgPath := filepath.Join(dir, ".pint.hcl")
+	if err := os.WriteFile(cfgPath, []byte("rule {\n  dependency {}\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "rules"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "rules", "0001.yml")
+	if err := os.WriteFile(other, []byte("- record: job:up:sum\n  expr: sum(up) by(job)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	edited := filepath.Join(dir, "rules", "0002.yml")
+	if err := os.WriteFile(edited, []byte("- record: job:up:count\n  expr: count(up) by(job)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unsaved content of the edited file is used instead of the file on disk
+	content := []byte("- alert: Job Is Down\n  expr: job:up:sum == 0 or job:up:count == 0\n")
+	missing := func(name string) checks.Problem {
+		return checks.Problem{
+			Fragment: name,
+			Lines:    []int{2},
+			Reporter: checks.DependencyCheckName,
+			Text:     name + " looks like a recording rule but it's not defined in any of the scanned files",
+			Severity: checks.Warning,
+		}
+	}
+
+	ws := newLSPWorkspace(dir, []string{filepath.Join(dir, "rules")})
+	problems := lintContent(cfg, "rules/0002.yml", content, ws.entriesExcept("rules/0002.yml"))
+	if diff := cmp.Diff([]checks.Problem{missing("job:up:count")}, problems); diff != "" {
+		t.Errorf("lintContent() with workspace returned wrong problems (-want +got):\n%s", diff)
+	}
+
+	// workspace files are only parsed again after a refresh
+	if err = os.WriteFile(filepath.Join(dir, "rules", "0003.yml"), []byte("- record: job:up:count\n  expr: count(up) by(job)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	problems = lintContent(cfg, "rules/0002.yml", content, ws.entriesExcept("rules/0002.yml"))
+	if diff := cmp.Diff([]checks.Problem{missing("job:up:count")}, problems); diff != "" {
+		t.Errorf("lintContent() with cached workspace returned wrong problems (-want +got):\n%s", diff)
+	}
+	ws.refresh()
+	problems = lintContent(cfg, "rules/0002.yml", content, ws.entriesExcept("rules/0002.yml"))
+	if len(problems) != 0 {
+		t.Errorf("lintContent() with refreshed workspace returned problems: %v", problems)
+	}
+
+	problems = lintContent(cfg, "rules/0002.yml", content, newLSPWorkspace(dir, nil).entriesExcept("rules/0002.yml"))
+	if diff := cmp.Diff([]checks.Problem{missing("job:up:sum"), missing("job:up:count")}, problems); diff != "" {
+		t.Errorf("lintContent() without workspace returned wrong problems (-want +got):\n%s", diff)
+	}
+}
@@ -38,19 +38,119 @@ func newApp() *cli.App {
 				Name:   "lint",
 				Usage:  "Lint specified files",
 				Action: actionLint,
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.StringSliceFlag{
 						Name:    disabledFlag,
 						Aliases: []string{"d"},
 						Value:   cli.NewStringSlice(),
 						Usage:   "List of checks to disable (example: promql/cost)",
 					},
-				},
+				}, append(outputFlags(), baselineFlags()...)...),
 			},
 			{
 				Name:   "ci",
 				Usage:  "Lint CI changes",
 				Action: actionCI,
+				Flags: append([]cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    disabledFlag,
+						Aliases: []string{"d"},
+						Value:   cli.NewStringSlice(),
+						Usage:   "List of checks to disable (example: promql/cost)",
+					},
+				}, append(outputFlags(), baselineFlags()...)...),
+			},
+			{
+				Name:   "baseline",
+				Usage:  "Save all problems found in specified files to a baseline file",
+				Action: actionBaseline,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    disabledFlag,
+						Aliases: []string{"d"},
+						Value:   cli.NewStringSlice(),
+						Usage:   "List of checks to disable (example: promql/cost)",
+					},
+					&cli.PathFlag{
+						Name:  baselineFlag,
+						Value: defaultBaselinePath,
+						Usage: "Baseline file to write",
+					},
+				},
+			},
+			{
+				Name:   "fix",
+				Usage:  "Apply suggested fixes to specified files",
+				Action: actionFix,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    disabledFlag,
+						Aliases: []string{"d"},
+						Value:   cli.NewStringSlice(),
+						Usage:   "List of checks to disable (example: promql/cost)",
+					},
+					&cli.BoolFlag{
+						Name:  dryRunFlag,
+						Usage: "Print a diff of suggested changes instead of modifying files",
+					},
+				},
+			},
+			{
+				Name:   "watch",
+				Usage:  "Continuously lint specified files and expose problems as Prometheus metrics",
+				Action: actionWatch,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    disabledFlag,
+						Aliases: []string{"d"},
+						Value:   cli.NewStringSlice(),
+						Usage:   "List of checks to disable (example: promql/cost)",
+					},
+					&cli.DurationFlag{
+						Name:  intervalFlag,
+						Value: time.Minute * 10,
+						Usage: "How often to run all checks",
+					},
+					&cli.StringFlag{
+						Name:  listenFlag,
+						Value: ":8080",
+						Usage: "Listen address for the HTTP server serving metrics",
+					},
+				},
+			},
+			{
+				Name:   "lsp",
+				Usage:  "Run a Language Server Protocol server over stdio, using rules from specified files as the workspace",
+				Action: actionLSP,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    disabledFlag,
+						Aliases: []string{"d"},
+						Value:   cli.NewStringSlice(),
+						Usage:   "List of checks to disable (example: promql/cost)",
+					},
+					&cli.BoolFlag{
+						Name:  onlineFlag,
+						Usage: "Also run checks that query Prometheus servers",
+					},
+					&cli.DurationFlag{
+						Name:  debounceFlag,
+						Value: time.Second * 2,
+						Usage: "How long to wait after the last change before running checks that query Prometheus",
+					},
+				},
+			},
+			{
+				Name:   "graph",
+				Usage:  "Print the dependency graph of rules in specified files",
+				Action: actionGraph,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  graphFormatFlag,
+						Value: "dot",
+						Usage: "Graph format, one of: dot, json",
+					},
+				},
 			},
 			{
 				Name:   "config",
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	outputFormatFlag = "output-format"
+	outputFileFlag   = "output-file"
+
+	consoleFormat       = "console"
+	jsonFormat          = "json"
+	sarifFormat         = "sarif"
+	checkstyleFormat    = "checkstyle"
+	junitFormat         = "junit"
+	githubActionsFormat = "github-actions"
+)
+
+var outputFormats = []string{consoleFormat, jsonFormat, sarifFormat, checkstyleFormat, junitFormat, githubActionsFormat}
+
+func outputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  outputFormatFlag,
+			Value: consoleFormat,
+			Usage: fmt.Sprintf("Format of the generated report (one of: %s)", strings.Join(outputFormats, ", ")),
+		},
+		&cli.PathFlag{
+			Name:  outputFileFlag,
+			Usage: "Write the report to this file instead of the default output",
+		},
+	}
+}
+
+// newOutputReporters returns reporters selected with --output-format flag.
+// Console report is always written to stderr, unless it's the selected format
+// and --output-file is set. Any other format is written to --output-file or
+// stdout if no file was specified.
+// When running inside GitHub Actions workflow commands are also printed to
+// stdout, unless stdout is already used by the selected format.
+// Returned closer must be called once all reports are submitted.
+func newOutputReporters(c *cli.Context) (reps []reporter.Reporter, closer func() error, err error) {
+	closer = func() error { return nil }
+
+	format := c.String(outputFormatFlag)
+	if !isValidOutputFormat(format) {
+		return nil, closer, fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	var output io.Writer = os.Stdout
+	if format == consoleFormat {
+		output = os.Stderr
+	}
+	if path := c.Path(outputFileFlag); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, closer, fmt.Errorf("failed to open output file: %w", err)
+		}
+		output = f
+		closer = f.Close
+	}
+
+	switch format {
+	case consoleFormat:
+		reps = append(reps, reporter.NewConsoleReporter(output))
+	case jsonFormat:
+		reps = append(reps, reporter.NewConsoleReporter(os.Stderr), reporter.NewJSONReporter(output))
+	case sarifFormat:
+		reps = append(reps, reporter.NewConsoleReporter(os.Stderr), reporter.NewSarifReporter(output))
+	case checkstyleFormat:
+		reps = append(reps, reporter.NewConsoleReporter(os.Stderr), reporter.NewCheckstyleReporter(output))
+	case junitFormat:
+		reps = append(reps, reporter.NewConsoleReporter(os.Stderr), reporter.NewJUnitReporter(output))
+	case githubActionsFormat:
+		reps = append(reps, reporter.NewConsoleReporter(os.Stderr), reporter.NewGitHubActionsReporter(output))
+	}
+
+	if format != githubActionsFormat && output != os.Stdout && os.Getenv("GITHUB_ACTIONS") == "true" {
+		reps = append(reps, reporter.NewGitHubActionsReporter(os.Stdout))
+	}
+
+	return reps, closer, nil
+}
+
+func isValidOutputFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rulesAtCommit parses given rule files at given commit, files not matching
+// include patterns are skipped, if there are no include patterns then all
+// YAML files are parsed
+func rulesAtCommit(gitCmd git.CommandRunner, commit string, paths []string, include []*regexp.Regexp) ([]graph.Entry, error) {
+	entries := []graph.Entry{}
+	p := parser.NewParser()
+	for _, path := range paths {
+		if !isRuleFile(path, include) {
+			continue
+		}
+		content, err := git.FileContent(gitCmd, commit, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path, commit, err)
+		}
+		rules, err := p.Parse(content)
+		if err != nil {
+			log.Debug().Str("path", path).Str("commit", commit).Err(err).Msg("Failed to parse file content")
+			continue
+		}
+		for _, rule := range rules {
+			entries = append(entries, graph.Entry{Path: path, Rule: rule})
+		}
+	}
+	return entries, nil
+}
+
+func isRuleFile(path string, include []*regexp.Regexp) bool {
+	if len(include) == 0 {
+		ext := filepath.Ext(path)
+		return ext == ".yml" || ext == ".yaml"
+	}
+	for _, pattern := range include {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRemovedRules compares rules at the merge base with the base branch
+// and rules at HEAD in files modified on this branch, it will report all recording rules removed on this
+// branch that are still used by other rules and all removed or renamed alerts
+func checkRemovedRules(gitCmd git.CommandRunner, baseBranch string, include []*regexp.Regexp) ([]reporter.Report, error) {
+	base, err := git.MergeBase(gitCmd, baseBranch)
+	if err != nil {
+		log.Warn().Str("branch", baseBranch).Err(err).Msg("Failed to find merge base, skipping removed rules check")
+		return nil, nil
+	}
+
+	// rules can only be removed from files modified on this branch
+	beforePaths, afterPaths, err := git.ChangedFiles(gitCmd, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files modified since %s: %w", base, err)
+	}
+	before, err := rulesAtCommit(gitCmd, base, beforePaths, include)
+	if err != nil {
+		return nil, err
+	}
+	after, err := rulesAtCommit(gitCmd, "HEAD", afterPaths, include)
+	if err != nil {
+		return nil, err
+	}
+	removed := graph.Removed(before, after)
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	// removed rules might still be defined in files that weren't modified and
+	// rules using them can be anywhere, so we also need to parse all files
+	// that mention any of the removed rules
+	names := []string{}
+	for _, rr := range removed {
+		names = append(names, rr.Rule.Name())
+	}
+	paths, err := git.GrepFiles(gitCmd, "HEAD", names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search files at HEAD: %w", err)
+	}
+	modified := map[string]struct{}{}
+	for _, path := range afterPaths {
+		modified[path] = struct{}{}
+	}
+	unmodified := []string{}
+	for _, path := range paths {
+		if _, ok := modified[path]; !ok {
+			unmodified = append(unmodified, path)
+		}
+	}
+	others, err := rulesAtCommit(gitCmd, "HEAD", unmodified, include)
+	if err != nil {
+		return nil, err
+	}
+	all := append(after, others...)
+
+	// renamed rules are only looked for in modified files, so we keep those
+	// and only drop rules that are still defined somewhere else
+	stillRemoved := map[string]struct{}{}
+	for _, rr := range graph.Removed(before, all) {
+		stillRemoved[rr.Path+"\n"+rr.Rule.Name()] = struct{}{}
+	}
+	filtered := []graph.RemovedRule{}
+	for _, rr := range removed {
+		if _, ok := stillRemoved[rr.Path+"\n"+rr.Rule.Name()]; ok {
+			filtered = append(filtered, rr)
+		}
+	}
+
+	return removedRuleReports(filtered, all), nil
+}
+
+func removedRuleReports(removed []graph.RemovedRule, after []graph.Entry) (reports []reporter.Report) {
+	for _, rr := range removed {
+		if rr.Rule.AlertingRule != nil {
+			if report, ok := removedAlertReport(rr, after); ok {
+				reports = append(reports, report)
+			}
+			continue
+		}
+
+		text := fmt.Sprintf("%s recording rule was removed from %s on this branch, but it's still used by this rule", rr.Rule.Name(), rr.Path)
+		if rr.RenamedTo != "" {
+			text = fmt.Sprintf("%s recording rule was renamed to %s on this branch, but it's still used by this rule", rr.Rule.Name(), rr.RenamedTo)
+		}
+
+		for _, entry := range after {
+			for _, name := range graph.MetricNames(entry.Rule) {
+				if name != rr.Rule.Name() {
+					continue
+				}
+				reports = append(reports, reporter.Report{
+					Path: entry.Path,
+					Rule: entry.Rule,
+					Problem: checks.Problem{
+						Fragment: name,
+						Lines:    entry.Rule.Expr().Lines(),
+						Reporter: checks.RemovedCheckName,
+						Text:     text,
+						Severity: checks.Bug,
+					},
+					External: true,
+				})
+			}
+		}
+	}
+	return reports
+}
+
+// removedAlertReport returns an informational report for a removed or renamed
+// alert, renamed alerts are reported on the new rule, removed alerts on the
+// group they were defined in, if it still exists
+func removedAlertReport(rr graph.RemovedRule, after []graph.Entry) (reporter.Report, bool) {
+	for _, entry := range after {
+		if rr.RenamedTo != "" {
+			if entry.Rule.AlertingRule == nil || entry.Rule.Name() != rr.RenamedTo {
+				continue
+			}
+			return reporter.Report{
+				Path: entry.Path,
+				Rule: entry.Rule,
+				Problem: checks.Problem{
+					Fragment: fmt.Sprintf("%s: %s", entry.Rule.AlertingRule.Alert.Key.Value, rr.RenamedTo),
+					Lines:    entry.Rule.AlertingRule.Alert.Lines(),
+					Reporter: checks.RemovedCheckName,
+					Text:     fmt.Sprintf("%s alerting rule was renamed to %s on this branch", rr.Rule.Name(), rr.RenamedTo),
+					Severity: checks.Information,
+				},
+				External: true,
+			}, true
+		}
+
+		if entry.Path != rr.Path || entry.Rule.Group == nil || entry.Rule.Group.Name == nil ||
+			rr.Rule.Group == nil || rr.Rule.Group.Name == nil || entry.Rule.Group.Name.Value.Value != rr.Rule.Group.Name.Value.Value {
+			continue
+		}
+		return reporter.Report{
+			Path: entry.Path,
+			Rule: entry.Rule,
+			Problem: checks.Problem{
+				Fragment: fmt.Sprintf("%s: %s", entry.Rule.Group.Name.Key.Value, entry.Rule.Group.Name.Value.Value),
+				Lines:    entry.Rule.Group.Name.Lines(),
+				Reporter: checks.RemovedCheckName,
+				Text:     fmt.Sprintf("%s alerting rule was removed from this group on this branch", rr.Rule.Name()),
+				Severity: checks.Information,
+			},
+			External: true,
+		}, true
+	}
+
+	// there's nothing left to attach this report to
+	log.Info().Str("path", rr.Path).Str("alert", rr.Rule.Name()).Msg("Alerting rule removed")
+	return reporter.Report{}, false
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestCheckRemovedRules(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	type testCaseT struct {
+		description string
+		files       map[string]map[string]string
+		reports     int
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "nothing removed",
+			files: map[string]map[string]string{
+				"base": {"rules.yml": "- record: foo\n  expr: sum(up)\n"},
+				"HEAD": {"rules.yml": "- record: foo\n  expr: sum(up) by(job)\n"},
+			},
+		},
+		{
+			description: "removed rule still used",
+			files: map[string]map[string]string{
+				"base": {"rules.yml": "- record: foo\n  expr: sum(up)\n"},
+				"HEAD": {"rules.yml": "- alert: foo\n  expr: up == 0\n", "other.yml": "- alert: bar\n  expr: foo == 0\n"},
+			},
+			reports: 1,
+		},
+		{
+			description: "removed rule still defined in another file",
+			files: map[string]map[string]string{
+				"base": {"rules.yml": "- record: foo\n  expr: sum(up)\n", "other.yml": "- record: foo\n  expr: sum(up)\n"},
+				"HEAD": {"rules.yml": "- alert: foo\n  expr: foo == 0\n", "other.yml": "- record: foo\n  expr: sum(up)\n"},
+			},
+		},
+		{
+			description: "unmodified files not using removed rules are not read",
+			files: map[string]map[string]string{
+				"base": {"rules.yml": "- record: foo\n  expr: sum(up)\n"},
+				"HEAD": {"rules.yml": "- alert: foo\n  expr: up == 0\n", "other.yml": "- alert: bar\n  expr: foo == 0\n", "broken.yml": "- alert: bar\n  expr: up == 0\n"},
+			},
+			reports: 1,
+		},
+		{
+			description: "no merge base",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			gitCmd := func(args ...string) ([]byte, error) {
+				switch args[0] {
+				case "merge-base":
+					if tc.files == nil {
+						return nil, errors.New("mock error")
+					}
+					return []byte("base\n"), nil
+				case "diff":
+					// only rules.yml is modified
+					return []byte("M\trules.yml\n"), nil
+				case "grep":
+					var out string
+					for path, content := range tc.files[args[len(args)-2]] {
+						for i, arg := range args {
+							if arg == "-e" && strings.Contains(content, args[i+1]) {
+								out += args[len(args)-2] + ":" + path + "\n"
+								break
+							}
+						}
+					}
+					return []byte(out), nil
+				case "show":
+					if args[1] == "HEAD:broken.yml" {
+						return nil, errors.New("file shouldn't be read")
+					}
+					for commit, files := range tc.files {
+						for path, content := range files {
+							if args[1] == commit+":"+path {
+								return []byte(content), nil
+							}
+						}
+					}
+				}
+				return nil, fmt.Errorf("unexpected git command: %v", args)
+			}
+
+			reports, err := checkRemovedRules(gitCmd, "main", nil)
+			if err != nil {
+				t.Fatalf("checkRemovedRules() returned an error: %s", err)
+			}
+			if len(reports) != tc.reports {
+				t.Errorf("checkRemovedRules() returned %d report(s), expected %d", len(reports), tc.reports)
+			}
+		})
+	}
+}
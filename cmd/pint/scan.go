@@ -9,6 +9,7 @@ import (
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/config"
 	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/graph"
 	"github.com/cloudflare/pint/internal/output"
 	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/reporter"
@@ -52,6 +53,10 @@ func scanFiles(cfg config.Config, fcs discovery.FileFindResults, ld discovery.Li
 
 	p := parser.NewParser()
 
+	// all parsed rules, including those that are not checked, are used to
+	// build a dependency graph
+	graphEntries := []graph.Entry{}
+
 	for _, path := range summary.FileChanges.Paths() {
 		path := path
 
@@ -77,7 +82,7 @@ func scanFiles(cfg config.Config, fcs discovery.FileFindResults, ld discovery.Li
 			continue
 		}
 
-		rules, err := p.Parse(content)
+		rules, groups, err := p.ParseWithGroups(content)
 		if err != nil {
 			summary.Reports = append(summary.Reports, scanProblem(path, err))
 			log.Error().Str("path", path).Err(err).Msg("Failed to parse file content")
@@ -85,8 +90,16 @@ func scanFiles(cfg config.Config, fcs discovery.FileFindResults, ld discovery.Li
 		}
 		log.Info().Str("path", path).Int("rules", len(rules)).Msg("File parsed")
 
+		for _, problem := range checkFile(cfg, path, content, groups) {
+			if !lineResults.HasLines(problem.Lines) {
+				continue
+			}
+			summary.Reports = append(summary.Reports, reporter.Report{Path: path, Problem: problem})
+		}
+
 		for _, rule := range rules {
 			rule := rule
+			graphEntries = append(graphEntries, graph.Entry{Path: path, Rule: rule})
 
 			if rule.AlertingRule != nil {
 				log.Debug().
@@ -110,6 +123,7 @@ func scanFiles(cfg config.Config, fcs discovery.FileFindResults, ld discovery.Li
 				log.Debug().Str("path", path).Str("lines", output.FormatLineRangeString(rule.Lines())).Msg("Skipping rule")
 				continue
 			}
+			summary.Entries = append(summary.Entries, reporter.Entry{Path: path, Rule: rule})
 
 			if rule.Error.Err == nil {
 				checkList := cfg.GetChecksForRule(path, rule)
@@ -123,6 +137,16 @@ func scanFiles(cfg config.Config, fcs discovery.FileFindResults, ld discovery.Li
 		}
 	}
 
+	g := graph.New(graphEntries)
+	for _, entry := range summary.Entries {
+		if entry.Rule.Error.Err != nil {
+			continue
+		}
+		for _, check := range cfg.GetChecksForRuleSet(entry.Path, entry.Rule, g) {
+			scanJobs = append(scanJobs, scanJob{path: entry.Path, rule: entry.Rule, check: check})
+		}
+	}
+
 	jobs := make(chan scanJob, 100)
 	results := make(chan reporter.Report, 100)
 	wg := sync.WaitGroup{}
@@ -164,13 +188,7 @@ func scanWorker(jobs <-chan scanJob, results chan<- reporter.Report) {
 	for job := range jobs {
 		job := job
 		if job.rule.Error.Err != nil {
-			results <- reporter.Report{Path: job.path, Rule: job.rule, Problem: checks.Problem{
-				Fragment: job.rule.Error.Fragment,
-				Lines:    []int{job.rule.Error.Line},
-				Reporter: "pint/parse",
-				Text:     job.rule.Error.Err.Error(),
-				Severity: checks.Fatal,
-			}}
+			results <- reporter.Report{Path: job.path, Rule: job.rule, Problem: ruleErrorProblem(job.rule)}
 		} else {
 			for _, problem := range job.check.Check(job.rule) {
 				results <- reporter.Report{Path: job.path, Rule: job.rule, Problem: problem}
@@ -179,6 +197,25 @@ func scanWorker(jobs <-chan scanJob, results chan<- reporter.Report) {
 	}
 }
 
+// checkFile runs all checks that validate the whole file instead of
+// individual rules
+func checkFile(cfg config.Config, path string, content []byte, groups []parser.RuleGroup) (problems []checks.Problem) {
+	for _, check := range cfg.GetChecksForFile(path) {
+		problems = append(problems, check.CheckFile(content, groups)...)
+	}
+	return problems
+}
+
+func ruleErrorProblem(rule parser.Rule) checks.Problem {
+	return checks.Problem{
+		Fragment: rule.Error.Fragment,
+		Lines:    []int{rule.Error.Line},
+		Reporter: "pint/parse",
+		Text:     rule.Error.Err.Error(),
+		Severity: checks.Fatal,
+	}
+}
+
 func submitReports(reps []reporter.Reporter, summary reporter.Summary) (err error) {
 	for _, rep := range reps {
 		err = rep.Submit(summary)
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	intervalFlag = "interval"
+	listenFlag   = "listen"
+)
+
+func actionWatch(c *cli.Context) (err error) {
+	err = initLogger(c.String(logLevelFlag))
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %s", err)
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	cfg, err := config.Load(c.Path(configFlag))
+	if err != nil {
+		return fmt.Errorf("failed to load config file %q: %s", c.Path(configFlag), err)
+	}
+
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+
+	interval := c.Duration(intervalFlag)
+	if interval <= 0 {
+		return fmt.Errorf("--%s must be greater than zero", intervalFlag)
+	}
+
+	collector := newProblemCollector()
+	prometheus.MustRegister(collector)
+	promapi.RegisterMetrics(prometheus.DefaultRegisterer)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: c.String(listenFlag), Handler: mux}
+
+	srvErr := make(chan error, 1)
+	go func() {
+		log.Info().Str("address", srv.Addr).Msg("Starting HTTP server")
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			srvErr <- err
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		collector.run(cfg, paths)
+
+		select {
+		case <-ticker.C:
+		case err = <-srvErr:
+			return fmt.Errorf("HTTP server failed: %w", err)
+		case sig := <-stop:
+			log.Info().Stringer("signal", sig).Msg("Shutting down")
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+			return srv.Shutdown(ctx)
+		}
+	}
+}
+
+// problemCollector exposes results of the most recent run as metrics
+type problemCollector struct {
+	lock         sync.Mutex
+	summary      reporter.Summary
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastFailed   bool
+
+	problem         *prometheus.Desc
+	problems        *prometheus.Desc
+	rules           *prometheus.Desc
+	lastRunTime     *prometheus.Desc
+	lastRunDuration *prometheus.Desc
+	lastRunError    *prometheus.Desc
+}
+
+func newProblemCollector() *problemCollector {
+	return &problemCollector{
+		problem: prometheus.NewDesc(
+			"pint_problem",
+			"Prometheus rule problem reported by pint",
+			[]string{"filename", "kind", "name", "reporter", "severity"},
+			nil,
+		),
+		problems: prometheus.NewDesc(
+			"pint_problems",
+			"Total number of problems reported by pint",
+			nil,
+			nil,
+		),
+		rules: prometheus.NewDesc(
+			"pint_rules",
+			"Total number of rules checked by pint",
+			nil,
+			nil,
+		),
+		lastRunTime: prometheus.NewDesc(
+			"pint_last_run_time_seconds",
+			"Last successful checks run completion time since unix epoch in seconds",
+			nil,
+			nil,
+		),
+		lastRunDuration: prometheus.NewDesc(
+			"pint_last_run_duration_seconds",
+			"Last successful checks run duration in seconds",
+			nil,
+			nil,
+		),
+		lastRunError: prometheus.NewDesc(
+			"pint_last_run_error",
+			"Set to 1 if the last checks run failed, 0 otherwise",
+			nil,
+			nil,
+		),
+	}
+}
+
+func (pc *problemCollector) run(cfg config.Config, paths []string) {
+	start := time.Now()
+	log.Info().Strs("paths", paths).Msg("Running checks")
+
+	d := discovery.NewGlobFileFinder()
+	toScan, err := d.Find(paths...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find files to check")
+		pc.fail()
+		return
+	}
+
+	summary := scanFiles(cfg, toScan, &discovery.NoopLineFinder{})
+	pc.update(summary, start, time.Since(start))
+	log.Info().Int("problems", len(summary.Reports)).Int("rules", len(summary.Entries)).Msg("Checks completed")
+}
+
+func (pc *problemCollector) update(summary reporter.Summary, lastRun time.Time, lastDuration time.Duration) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.summary = summary
+	pc.lastRun = lastRun.Add(lastDuration)
+	pc.lastDuration = lastDuration
+	pc.lastFailed = false
+}
+
+// fail drops results of the previous run, so those are not exported as if
+// they were current, and marks the last run as failed
+func (pc *problemCollector) fail() {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.summary = reporter.Summary{}
+	pc.lastFailed = true
+}
+
+func (pc *problemCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pc.problem
+	ch <- pc.problems
+	ch <- pc.rules
+	ch <- pc.lastRunTime
+	ch <- pc.lastRunDuration
+	ch <- pc.lastRunError
+}
+
+func (pc *problemCollector) Collect(ch chan<- prometheus.Metric) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	// the same problem can be reported multiple times for a single rule
+	type problemKey struct {
+		filename, kind, name, reporter, severity string
+	}
+	counts := map[problemKey]int{}
+	for _, report := range pc.summary.Reports {
+		counts[problemKey{
+			filename: report.Path,
+			kind:     ruleKind(report),
+			name:     report.Rule.Name(),
+			reporter: report.Problem.Reporter,
+			severity: report.Problem.Severity.String(),
+		}]++
+	}
+	for k, v := range counts {
+		ch <- prometheus.MustNewConstMetric(pc.problem, prometheus.GaugeValue, float64(v), k.filename, k.kind, k.name, k.reporter, k.severity)
+	}
+
+	ch <- prometheus.MustNewConstMetric(pc.problems, prometheus.GaugeValue, float64(len(pc.summary.Reports)))
+	ch <- prometheus.MustNewConstMetric(pc.rules, prometheus.GaugeValue, float64(len(pc.summary.Entries)))
+	if !pc.lastRun.IsZero() {
+		ch <- prometheus.MustNewConstMetric(pc.lastRunTime, prometheus.GaugeValue, float64(pc.lastRun.Unix()))
+		ch <- prometheus.MustNewConstMetric(pc.lastRunDuration, prometheus.GaugeValue, pc.lastDuration.Seconds())
+	}
+
+	var lastRunError float64
+	if pc.lastFailed {
+		lastRunError = 1
+	}
+	ch <- prometheus.MustNewConstMetric(pc.lastRunError, prometheus.GaugeValue, lastRunError)
+}
+
+func ruleKind(report reporter.Report) string {
+	switch {
+	case report.Rule.AlertingRule != nil:
+		return "alerting"
+	case report.Rule.RecordingRule != nil:
+		return "recording"
+	default:
+		return "invalid"
+	}
+}
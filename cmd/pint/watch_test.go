@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestProblemCollector(t *testing.T) {
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		output      string
+	}
+
+	rule := parser.Rule{
+		RecordingRule: &parser.RecordingRule{
+			Record: parser.YamlKeyValue{Value: &parser.YamlNode{Value: "foo"}},
+		},
+	}
+	problem := checks.Problem{
+		Lines:    []int{2},
+		Reporter: "promql/rate",
+		Text:     "duration for rate() is too small",
+		Severity: checks.Bug,
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no problems",
+			summary: reporter.Summary{
+				Entries: []reporter.Entry{{Path: "rules.yml", Rule: rule}},
+			},
+			output: `
+# HELP pint_problems Total number of problems reported by pint
+# TYPE pint_problems gauge
+pint_problems 0
+# HELP pint_rules Total number of rules checked by pint
+# TYPE pint_rules gauge
+pint_rules 1
+`,
+		},
+		{
+			description: "duplicated problems",
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{Path: "rules.yml", Rule: rule, Problem: problem},
+					{Path: "rules.yml", Rule: rule, Problem: problem},
+				},
+				Entries: []reporter.Entry{{Path: "rules.yml", Rule: rule}},
+			},
+			output: `
+# HELP pint_problem Prometheus rule problem reported by pint
+# TYPE pint_problem gauge
+pint_problem{filename="rules.yml",kind="recording",name="foo",reporter="promql/rate",severity="Bug"} 2
+# HELP pint_problems Total number of problems reported by pint
+# TYPE pint_problems gauge
+pint_problems 2
+# HELP pint_rules Total number of rules checked by pint
+# TYPE pint_rules gauge
+pint_rules 1
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			pc := newProblemCollector()
+			pc.update(tc.summary, time.Unix(1000, 0), time.Second)
+
+			reg := prometheus.NewPedanticRegistry()
+			reg.MustRegister(pc)
+
+			err := testutil.GatherAndCompare(reg, strings.NewReader(tc.output), "pint_problem", "pint_problems", "pint_rules")
+			if err != nil {
+				t.Error(err)
+			}
+			if n := testutil.CollectAndCount(pc, "pint_last_run_duration_seconds"); n != 1 {
+				t.Errorf("expected pint_last_run_duration_seconds to be exported, got %d metrics", n)
+			}
+		})
+	}
+}
+
+func TestProblemCollectorFailedRun(t *testing.T) {
+	rule := parser.Rule{
+		RecordingRule: &parser.RecordingRule{
+			Record: parser.YamlKeyValue{Value: &parser.YamlNode{Value: "foo"}},
+		},
+	}
+
+	pc := newProblemCollector()
+	pc.update(reporter.Summary{
+		Reports: []reporter.Report{
+			{Path: "rules.yml", Rule: rule, Problem: checks.Problem{Reporter: "promql/rate", Severity: checks.Bug}},
+		},
+		Entries: []reporter.Entry{{Path: "rules.yml", Rule: rule}},
+	}, time.Unix(1000, 0), time.Second)
+	pc.run(config.Config{}, []string{"[invalid"})
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(pc)
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP pint_last_run_error Set to 1 if the last checks run failed, 0 otherwise
+# TYPE pint_last_run_error gauge
+pint_last_run_error 1
+# HELP pint_last_run_time_seconds Last successful checks run completion time since unix epoch in seconds
+# TYPE pint_last_run_time_seconds gauge
+pint_last_run_time_seconds 1001
+# HELP pint_problems Total number of problems reported by pint
+# TYPE pint_problems gauge
+pint_problems 0
+# HELP pint_rules Total number of rules checked by pint
+# TYPE pint_rules gauge
+pint_rules 0
+`), "pint_problem", "pint_problems", "pint_rules", "pint_last_run_time_seconds", "pint_last_run_error")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -7,6 +7,7 @@ require (
 	github.com/getsentry/sentry-go v0.11.0
 	github.com/google/go-cmp v0.5.6
 	github.com/hashicorp/hcl/v2 v2.10.0
+	github.com/pkg/diff v0.0.0-20210226163009-20ebb0f2a09e
 	github.com/prometheus/client_golang v1.10.0
 	github.com/prometheus/common v0.25.0
 	github.com/prometheus/prometheus v1.8.2-0.20210331101223-3cafc58827d1
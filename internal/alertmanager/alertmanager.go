@@ -0,0 +1,303 @@
+package alertmanager
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"gopkg.in/yaml.v3"
+)
+
+var matcherRe = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+// Config is the subset of Alertmanager configuration needed to route alerts
+// to receivers, it only supports the route tree and inhibition rules
+type Config struct {
+	Route        *Route        `yaml:"route"`
+	InhibitRules []InhibitRule `yaml:"inhibit_rules"`
+	Receivers    []Receiver    `yaml:"receivers"`
+}
+
+type Receiver struct {
+	Name string `yaml:"name"`
+}
+
+// Route is a single node of the routing tree, routes without a receiver
+// inherit it from the parent route
+type Route struct {
+	Receiver string            `yaml:"receiver"`
+	Match    map[string]string `yaml:"match"`
+	MatchRE  map[string]string `yaml:"match_re"`
+	Matchers []string          `yaml:"matchers"`
+	Continue bool              `yaml:"continue"`
+	Routes   []*Route          `yaml:"routes"`
+
+	matchers []*labels.Matcher
+}
+
+type InhibitRule struct {
+	SourceMatch    map[string]string `yaml:"source_match"`
+	SourceMatchRE  map[string]string `yaml:"source_match_re"`
+	SourceMatchers []string          `yaml:"source_matchers"`
+	TargetMatch    map[string]string `yaml:"target_match"`
+	TargetMatchRE  map[string]string `yaml:"target_match_re"`
+	TargetMatchers []string          `yaml:"target_matchers"`
+	Equal          []string          `yaml:"equal"`
+
+	source []*labels.Matcher
+	target []*labels.Matcher
+}
+
+// Load reads and parses Alertmanager configuration file
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Alertmanager config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse parses Alertmanager configuration
+func Parse(content []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Route == nil {
+		return nil, fmt.Errorf("missing route")
+	}
+	if cfg.Route.Receiver == "" {
+		return nil, fmt.Errorf("root route must specify a default receiver")
+	}
+	if err := cfg.Route.compile(""); err != nil {
+		return nil, err
+	}
+
+	receivers := map[string]struct{}{}
+	for _, r := range cfg.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+	if len(cfg.Receivers) > 0 {
+		for _, name := range cfg.Route.receivers() {
+			if _, ok := receivers[name]; !ok {
+				return nil, fmt.Errorf("undefined receiver %q used in route", name)
+			}
+		}
+	}
+
+	for i := range cfg.InhibitRules {
+		if err := cfg.InhibitRules[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
+
+func (r *Route) compile(parent string) (err error) {
+	if r.Receiver == "" {
+		r.Receiver = parent
+	}
+	if r.matchers, err = compileMatchers(r.Match, r.MatchRE, r.Matchers); err != nil {
+		return err
+	}
+	for _, child := range r.Routes {
+		if err = child.compile(r.Receiver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Route) receivers() (names []string) {
+	names = append(names, r.Receiver)
+	for _, child := range r.Routes {
+		names = append(names, child.receivers()...)
+	}
+	return names
+}
+
+func (r *Route) matches(lset map[string]string) bool {
+	return matchAll(r.matchers, lset)
+}
+
+// match returns all routes matching given labels, following the same logic
+// as Alertmanager, routes are checked depth first and only the first matching
+// child route is used unless it has continue set
+func (r *Route) match(lset map[string]string) (routes []*Route) {
+	if !r.matches(lset) {
+		return nil
+	}
+	for _, child := range r.Routes {
+		matched := child.match(lset)
+		routes = append(routes, matched...)
+		if matched != nil && !child.Continue {
+			break
+		}
+	}
+	if len(routes) == 0 {
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// Match returns all routes given alert labels would be sent to
+func (cfg *Config) Match(lset map[string]string) []*Route {
+	return cfg.Route.match(lset)
+}
+
+// IsDefault returns true if given list of routes only contains the root route
+func (cfg *Config) IsDefault(routes []*Route) bool {
+	return len(routes) == 1 && routes[0] == cfg.Route
+}
+
+// LabelMatchers returns all equality and regexp matchers for given label
+// name used by any route in the tree
+func (cfg *Config) LabelMatchers(name string) (ms []*labels.Matcher) {
+	seen := map[string]struct{}{}
+	cfg.Route.walk(func(r *Route) {
+		for _, m := range r.matchers {
+			if m.Name != name || (m.Type != labels.MatchEqual && m.Type != labels.MatchRegexp) {
+				continue
+			}
+			if _, ok := seen[m.String()]; ok {
+				continue
+			}
+			seen[m.String()] = struct{}{}
+			ms = append(ms, m)
+		}
+	})
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].String() < ms[j].String()
+	})
+	return ms
+}
+
+// UsesLabel returns true if any route in the tree has a matcher for given
+// label name
+func (cfg *Config) UsesLabel(name string) (ok bool) {
+	cfg.Route.walk(func(r *Route) {
+		for _, m := range r.matchers {
+			if m.Name == name {
+				ok = true
+			}
+		}
+	})
+	return ok
+}
+
+func (r *Route) walk(fn func(*Route)) {
+	fn(r)
+	for _, child := range r.Routes {
+		child.walk(fn)
+	}
+}
+
+// Inhibits returns true if an alert with source labels would inhibit an alert
+// with target labels
+func (cfg *Config) Inhibits(source, target map[string]string) bool {
+	for _, ir := range cfg.InhibitRules {
+		if ir.inhibits(source, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ir *InhibitRule) compile() (err error) {
+	if ir.source, err = compileMatchers(ir.SourceMatch, ir.SourceMatchRE, ir.SourceMatchers); err != nil {
+		return err
+	}
+	if ir.target, err = compileMatchers(ir.TargetMatch, ir.TargetMatchRE, ir.TargetMatchers); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ir InhibitRule) inhibits(source, target map[string]string) bool {
+	if !matchAll(ir.target, target) || !matchAll(ir.source, source) {
+		return false
+	}
+	// alerts matching both sides can't inhibit themselves
+	if matchAll(ir.target, source) && matchAll(ir.source, target) {
+		return false
+	}
+	for _, name := range ir.Equal {
+		if source[name] != target[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func compileMatchers(match, matchRE map[string]string, matchers []string) (ms []*labels.Matcher, err error) {
+	for name, value := range match {
+		ms = append(ms, labels.MustNewMatcher(labels.MatchEqual, name, value))
+	}
+	for name, value := range matchRE {
+		m, err := labels.NewMatcher(labels.MatchRegexp, name, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp %q for %s: %w", value, name, err)
+		}
+		ms = append(ms, m)
+	}
+	for _, s := range matchers {
+		m, err := parseMatcher(s)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func parseMatcher(s string) (*labels.Matcher, error) {
+	parts := matcherRe.FindStringSubmatch(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "{"), "}"))
+	if parts == nil {
+		return nil, fmt.Errorf("invalid matcher %q", s)
+	}
+
+	value := parts[3]
+	if strings.HasPrefix(value, `"`) {
+		v, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		value = v
+	}
+
+	var mt labels.MatchType
+	switch parts[2] {
+	case "=":
+		mt = labels.MatchEqual
+	case "!=":
+		mt = labels.MatchNotEqual
+	case "=~":
+		mt = labels.MatchRegexp
+	case "!~":
+		mt = labels.MatchNotRegexp
+	}
+	m, err := labels.NewMatcher(mt, parts[1], value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matcher %q: %w", s, err)
+	}
+	return m, nil
+}
+
+func matchAll(ms []*labels.Matcher, lset map[string]string) bool {
+	for _, m := range ms {
+		if !m.Matches(lset[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
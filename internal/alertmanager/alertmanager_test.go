@@ -0,0 +1,240 @@
+package alertmanager_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/alertmanager"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testConfig = `
+route:
+  receiver: default
+  routes:
+  - match:
+      team: db
+    receiver: db
+    continue: true
+  - match_re:
+      team: web|api
+    receiver: web
+    routes:
+    - matchers:
+      - severity="critical"
+      receiver: web-pager
+  - matchers: [ 'team=~"db|cache"' ]
+    receiver: storage
+inhibit_rules:
+- source_matchers: [ severity=critical ]
+  target_matchers: [ severity=warning ]
+  equal: [ cluster ]
+receivers:
+- name: default
+- name: db
+- name: web
+- name: web-pager
+- name: storage
+`
+
+func TestParse(t *testing.T) {
+	type testCaseT struct {
+		content string
+		err     string
+	}
+
+	testCases := []testCaseT{
+		{
+			content: testConfig,
+		},
+		{
+			content: "receivers: []\n",
+			err:     "missing route",
+		},
+		{
+			content: "route:\n  routes:\n  - receiver: foo\n",
+			err:     "root route must specify a default receiver",
+		},
+		{
+			content: "route:\n  receiver: foo\n  routes:\n  - receiver: bar\nreceivers:\n- name: foo\n",
+			err:     `undefined receiver "bar" used in route`,
+		},
+		{
+			content: "route:\n  receiver: foo\n  routes:\n  - matchers: [ 'foo' ]\n",
+			err:     `invalid matcher "foo"`,
+		},
+		{
+			content: "route:\n  receiver: foo\n  routes:\n  - match_re:\n      foo: '('\n",
+			err:     "invalid regexp \"(\" for foo: error parsing regexp: missing closing ): `^(?:()$`",
+		},
+		{
+			content: "route: [",
+			err:     "yaml: line 1: did not find expected node content",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.content, func(t *testing.T) {
+			_, err := alertmanager.Parse([]byte(tc.content))
+			if err == nil && tc.err != "" {
+				t.Errorf("Parse() returned nil error, expected: %s", tc.err)
+			} else if err != nil && err.Error() != tc.err {
+				t.Errorf("Parse() returned wrong error, expected %q, got %q", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	type testCaseT struct {
+		labels    map[string]string
+		receivers []string
+		isDefault bool
+	}
+
+	testCases := []testCaseT{
+		{
+			labels:    map[string]string{},
+			receivers: []string{"default"},
+			isDefault: true,
+		},
+		{
+			labels:    map[string]string{"team": "foo"},
+			receivers: []string{"default"},
+			isDefault: true,
+		},
+		{
+			labels:    map[string]string{"team": "db"},
+			receivers: []string{"db", "storage"},
+		},
+		{
+			labels:    map[string]string{"team": "cache"},
+			receivers: []string{"storage"},
+		},
+		{
+			labels:    map[string]string{"team": "api"},
+			receivers: []string{"web"},
+		},
+		{
+			labels:    map[string]string{"team": "api", "severity": "critical"},
+			receivers: []string{"web-pager"},
+		},
+	}
+
+	cfg, err := alertmanager.Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.receivers[0], func(t *testing.T) {
+			routes := cfg.Match(tc.labels)
+			receivers := []string{}
+			for _, route := range routes {
+				receivers = append(receivers, route.Receiver)
+			}
+			if diff := cmp.Diff(tc.receivers, receivers); diff != "" {
+				t.Errorf("Match() returned wrong receivers (-want +got):\n%s", diff)
+			}
+			if isDefault := cfg.IsDefault(routes); isDefault != tc.isDefault {
+				t.Errorf("IsDefault() returned %v, expected %v", isDefault, tc.isDefault)
+			}
+		})
+	}
+}
+
+func TestInhibits(t *testing.T) {
+	type testCaseT struct {
+		source   map[string]string
+		target   map[string]string
+		inhibits bool
+	}
+
+	testCases := []testCaseT{
+		{
+			source:   map[string]string{"severity": "critical", "cluster": "a"},
+			target:   map[string]string{"severity": "warning", "cluster": "a"},
+			inhibits: true,
+		},
+		{
+			source:   map[string]string{"severity": "critical", "cluster": "a"},
+			target:   map[string]string{"severity": "warning", "cluster": "b"},
+			inhibits: false,
+		},
+		{
+			source:   map[string]string{"severity": "warning", "cluster": "a"},
+			target:   map[string]string{"severity": "critical", "cluster": "a"},
+			inhibits: false,
+		},
+		{
+			source:   map[string]string{"severity": "critical"},
+			target:   map[string]string{"severity": "warning"},
+			inhibits: true,
+		},
+	}
+
+	cfg, err := alertmanager.Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source["severity"]+"/"+tc.target["cluster"], func(t *testing.T) {
+			if inhibits := cfg.Inhibits(tc.source, tc.target); inhibits != tc.inhibits {
+				t.Errorf("Inhibits() returned %v, expected %v", inhibits, tc.inhibits)
+			}
+		})
+	}
+}
+
+func TestLabelMatchers(t *testing.T) {
+	type testCaseT struct {
+		name     string
+		matchers []string
+	}
+
+	testCases := []testCaseT{
+		{
+			name:     "team",
+			matchers: []string{`team="db"`, `team=~"db|cache"`, `team=~"web|api"`},
+		},
+		{
+			name:     "severity",
+			matchers: []string{`severity="critical"`},
+		},
+		{
+			name:     "cluster",
+			matchers: []string{},
+		},
+	}
+
+	cfg, err := alertmanager.Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matchers := []string{}
+			for _, m := range cfg.LabelMatchers(tc.name) {
+				matchers = append(matchers, m.String())
+			}
+			if diff := cmp.Diff(tc.matchers, matchers); diff != "" {
+				t.Errorf("LabelMatchers() returned wrong matchers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUsesLabel(t *testing.T) {
+	cfg, err := alertmanager.Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, uses := range map[string]bool{"team": true, "severity": true, "cluster": false} {
+		if ok := cfg.UsesLabel(name); ok != uses {
+			t.Errorf("UsesLabel(%q) returned %v, expected %v", name, ok, uses)
+		}
+	}
+}
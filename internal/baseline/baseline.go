@@ -0,0 +1,111 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+// Entry is a single problem recorded in the baseline file, Count is the
+// number of reports with the same fingerprint
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	Path        string `json:"path"`
+	Name        string `json:"name,omitempty"`
+	Reporter    string `json:"reporter"`
+	Text        string `json:"text"`
+	Count       int    `json:"count"`
+}
+
+type Baseline struct {
+	Entries []Entry `json:"entries"`
+}
+
+// New creates a baseline with all given reports
+func New(reports []reporter.Report) Baseline {
+	idx := map[string]int{}
+	b := Baseline{Entries: []Entry{}}
+	for _, report := range reports {
+		fp := report.Fingerprint()
+		if i, ok := idx[fp]; ok {
+			b.Entries[i].Count++
+			continue
+		}
+		idx[fp] = len(b.Entries)
+		b.Entries = append(b.Entries, Entry{
+			Fingerprint: fp,
+			Path:        report.Path,
+			Name:        report.Rule.Name(),
+			Reporter:    report.Problem.Reporter,
+			Text:        report.Problem.Text,
+			Count:       1,
+		})
+	}
+
+	sort.Slice(b.Entries, func(i, j int) bool {
+		if b.Entries[i].Path != b.Entries[j].Path {
+			return b.Entries[i].Path < b.Entries[j].Path
+		}
+		if b.Entries[i].Name != b.Entries[j].Name {
+			return b.Entries[i].Name < b.Entries[j].Name
+		}
+		if b.Entries[i].Reporter != b.Entries[j].Reporter {
+			return b.Entries[i].Reporter < b.Entries[j].Reporter
+		}
+		return b.Entries[i].Text < b.Entries[j].Text
+	})
+
+	return b
+}
+
+func Load(path string) (b Baseline, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	if err = json.Unmarshal(content, &b); err != nil {
+		return b, fmt.Errorf("failed to decode baseline file: %w", err)
+	}
+	return b, nil
+}
+
+func (b Baseline) Save(path string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Filter removes all reports matching baseline entries and returns the rest,
+// together with all entries that didn't match enough reports.
+// Every entry will hide at most Count reports, any extra report with the same
+// fingerprint is a new problem.
+func (b Baseline) Filter(reports []reporter.Report) (kept []reporter.Report, stale []Entry) {
+	left := map[string]int{}
+	for _, e := range b.Entries {
+		left[e.Fingerprint] += e.Count
+	}
+
+	for _, report := range reports {
+		fp := report.Fingerprint()
+		if left[fp] > 0 {
+			left[fp]--
+			continue
+		}
+		kept = append(kept, report)
+	}
+
+	for _, e := range b.Entries {
+		if n := left[e.Fingerprint]; n > 0 {
+			e.Count = n
+			left[e.Fingerprint] = 0
+			stale = append(stale, e)
+		}
+	}
+
+	return kept, stale
+}
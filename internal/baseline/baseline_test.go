@@ -0,0 +1,139 @@
+package baseline_test
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/baseline"
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBaseline(t *testing.T) {
+	type testCaseT struct {
+		description string
+		baseline    []reporter.Report
+		reports     []reporter.Report
+		kept        []reporter.Report
+		stale       []baseline.Entry
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: foo
+  expr: sum(foo)
+- alert: bar
+  expr: up == 0
+`))
+
+	report := func(path string, rule parser.Rule, line int, text string) reporter.Report {
+		return reporter.Report{
+			Path: path,
+			Rule: rule,
+			Problem: checks.Problem{
+				Lines:    []int{line},
+				Reporter: "mock",
+				Text:     text,
+				Severity: checks.Bug,
+			},
+		}
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "empty baseline",
+			reports:     []reporter.Report{report("foo.yml", mockRules[0], 2, "mock")},
+			kept:        []reporter.Report{report("foo.yml", mockRules[0], 2, "mock")},
+		},
+		{
+			description: "line numbers are ignored",
+			baseline:    []reporter.Report{report("foo.yml", mockRules[0], 2, "mock")},
+			reports: []reporter.Report{
+				report("foo.yml", mockRules[0], 12, "mock"),
+				report("foo.yml", mockRules[1], 14, "mock"),
+				report("bar.yml", mockRules[0], 2, "mock"),
+				report("foo.yml", mockRules[0], 12, "other"),
+			},
+			kept: []reporter.Report{
+				report("foo.yml", mockRules[1], 14, "mock"),
+				report("bar.yml", mockRules[0], 2, "mock"),
+				report("foo.yml", mockRules[0], 12, "other"),
+			},
+		},
+		{
+			description: "duplicated problems are counted",
+			baseline: []reporter.Report{
+				report("foo.yml", mockRules[0], 2, "mock"),
+				report("foo.yml", mockRules[0], 3, "mock"),
+			},
+			reports: []reporter.Report{
+				report("foo.yml", mockRules[0], 2, "mock"),
+				report("foo.yml", mockRules[0], 3, "mock"),
+				report("foo.yml", mockRules[0], 4, "mock"),
+			},
+			kept: []reporter.Report{
+				report("foo.yml", mockRules[0], 4, "mock"),
+			},
+		},
+		{
+			description: "stale entries",
+			baseline: []reporter.Report{
+				report("foo.yml", mockRules[0], 2, "mock"),
+				report("foo.yml", mockRules[0], 3, "mock"),
+				report("foo.yml", mockRules[1], 4, "fixed"),
+			},
+			reports: []reporter.Report{
+				report("foo.yml", mockRules[0], 2, "mock"),
+			},
+			stale: []baseline.Entry{
+				{
+					Path:     "foo.yml",
+					Name:     "bar",
+					Reporter: "mock",
+					Text:     "fixed",
+					Count:    1,
+				},
+				{
+					Path:     "foo.yml",
+					Name:     "foo",
+					Reporter: "mock",
+					Text:     "mock",
+					Count:    1,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			file := path.Join(t.TempDir(), "baseline.json")
+			if err := baseline.New(tc.baseline).Save(file); err != nil {
+				t.Fatalf("Save() returned an error: %s", err)
+			}
+			b, err := baseline.Load(file)
+			if err != nil {
+				t.Fatalf("Load() returned an error: %s", err)
+			}
+
+			kept, stale := b.Filter(tc.reports)
+			if diff := cmp.Diff(describeReports(tc.kept), describeReports(kept)); diff != "" {
+				t.Errorf("Filter() returned wrong reports (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.stale, stale, cmpopts.IgnoreFields(baseline.Entry{}, "Fingerprint")); diff != "" {
+				t.Errorf("Filter() returned wrong stale entries (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func describeReports(reports []reporter.Report) (s []string) {
+	for _, r := range reports {
+		s = append(s, fmt.Sprintf("%s:%d: %s (%s) %s", r.Path, r.Problem.Lines[0], r.Rule.Name(), r.Problem.Reporter, r.Problem.Text))
+	}
+	return s
+}
@@ -3,34 +3,56 @@ package checks
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/cloudflare/pint/internal/alertmanager"
 	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
+
+	"github.com/prometheus/common/model"
 )
 
 const (
 	AlertsCheckName = "alerts/count"
 )
 
-func NewAlertsCheck(name, uri string, timeout, lookBack, step, resolve time.Duration) AlertsCheck {
+func NewAlertsCheck(name, uri string, timeout, lookBack, step, resolve time.Duration, labels []string, maxAlerts, maxFlapping int, defaultRoute bool, severity Severity, am *alertmanager.Config) AlertsCheck {
 	return AlertsCheck{
-		name:     name,
-		uri:      uri,
-		timeout:  timeout,
-		lookBack: lookBack,
-		step:     step,
-		resolve:  resolve,
+		name:         name,
+		uri:          uri,
+		timeout:      timeout,
+		lookBack:     lookBack,
+		step:         step,
+		resolve:      resolve,
+		labels:       labels,
+		maxAlerts:    maxAlerts,
+		maxFlapping:  maxFlapping,
+		defaultRoute: defaultRoute,
+		severity:     severity,
+		am:           am,
 	}
 }
 
+// AlertsCheck estimates how many alerts would be triggered by an alerting
+// rule, how long they would be firing for and how many of them would be
+// flapping, alerts are flapping if they resolve and fire again within
+// the resolve duration, if Alertmanager config is set then all alerts are
+// also routed to receivers and alerts only matching the default route raise
+// the severity when defaultRoute is set
 type AlertsCheck struct {
-	name     string
-	uri      string
-	timeout  time.Duration
-	lookBack time.Duration
-	step     time.Duration
-	resolve  time.Duration
+	name         string
+	uri          string
+	timeout      time.Duration
+	lookBack     time.Duration
+	step         time.Duration
+	resolve      time.Duration
+	labels       []string
+	maxAlerts    int
+	maxFlapping  int
+	defaultRoute bool
+	severity     Severity
+	am           *alertmanager.Config
 }
 
 func (c AlertsCheck) String() string {
@@ -46,6 +68,22 @@ func (c AlertsCheck) Check(rule parser.Rule) (problems []Problem) {
 		return
 	}
 
+	var forDur time.Duration
+	if rule.AlertingRule.For != nil {
+		d, err := model.ParseDuration(rule.AlertingRule.For.Value.Value)
+		if err != nil {
+			problems = append(problems, Problem{
+				Fragment: rule.AlertingRule.For.Value.Value,
+				Lines:    rule.AlertingRule.For.Lines(),
+				Reporter: AlertsCheckName,
+				Text:     fmt.Sprintf("can't simulate alerts using %s, failed to parse for value: %s", c.name, err),
+				Severity: Bug,
+			})
+			return
+		}
+		forDur = time.Duration(d)
+	}
+
 	end := time.Now()
 	start := end.Add(-1 * c.lookBack)
 
@@ -61,34 +99,28 @@ func (c AlertsCheck) Check(rule parser.Rule) (problems []Problem) {
 		return
 	}
 
-	var forDur time.Duration
-	if rule.AlertingRule.For != nil {
-		forDur, _ = time.ParseDuration(rule.AlertingRule.For.Value.Value)
-	}
-
-	var alerts int
+	var alerts, flapping int
+	durations := []time.Duration{}
+	counts := map[string]map[string]int{}
+	simulated := []simulatedAlert{}
 	for _, sample := range qr.Samples {
-		var isAlerting, isNew bool
-		var firstTime, lastTime time.Time
-		for _, value := range sample.Values {
-			isNew = value.Timestamp.Time().After(lastTime.Add(c.step))
-			if isNew {
-				if rule.AlertingRule.For != nil {
-					isAlerting = false
-				} else {
-					isAlerting = true
-					alerts++
-				}
-				firstTime = value.Timestamp.Time()
-			} else {
-				if !isAlerting && rule.AlertingRule.For != nil {
-					if !value.Timestamp.Time().Before(firstTime.Add(forDur)) {
-						isAlerting = true
-						alerts++
-					}
+		fired := c.firingPeriods(sample.Values, forDur)
+		lset := alertLabels(rule, sample.Metric, sample.Values)
+		for i, fp := range fired {
+			alerts++
+			durations = append(durations, fp.end.Sub(fp.start)+c.step)
+			if i > 0 && !fp.start.After(fired[i-1].end.Add(c.resolve)) {
+				flapping++
+			}
+		}
+		if len(fired) > 0 {
+			for _, name := range c.labels {
+				if _, ok := counts[name]; !ok {
+					counts[name] = map[string]int{}
 				}
+				counts[name][lset[name]] += len(fired)
 			}
-			lastTime = value.Timestamp.Time()
+			simulated = append(simulated, simulatedAlert{labels: lset, periods: fired})
 		}
 	}
 
@@ -100,12 +132,197 @@ func (c AlertsCheck) Check(rule parser.Rule) (problems []Problem) {
 	sort.Ints(lines)
 
 	delta := qr.End.Sub(qr.Start)
+	text := fmt.Sprintf("query using %s would trigger %d alert(s) in the last %s", c.name, alerts, promapi.HumanizeDuration(delta))
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		text += fmt.Sprintf(", median alert duration is %s, longest alert lasted %s",
+			promapi.HumanizeDuration(durations[len(durations)/2]),
+			promapi.HumanizeDuration(durations[len(durations)-1]))
+	}
+	if flapping > 0 {
+		text += fmt.Sprintf(", %d alert(s) would resolve and fire again within %s", flapping, promapi.HumanizeDuration(c.resolve))
+	}
+	for _, name := range c.labels {
+		if _, ok := counts[name]; ok {
+			text += ", " + formatLabelCounts(name, counts[name])
+		}
+	}
+
+	severity := Information
+	if c.maxAlerts > 0 && alerts > c.maxAlerts {
+		severity = c.severity
+		text += fmt.Sprintf(", maximum allowed alerts is %d", c.maxAlerts)
+	}
+	if c.maxFlapping > 0 && flapping > c.maxFlapping {
+		severity = c.severity
+		text += fmt.Sprintf(", maximum allowed flapping alerts is %d", c.maxFlapping)
+	}
+
+	if c.am != nil && len(simulated) > 0 {
+		receivers, inhibited, defaultOnly := c.routeAlerts(simulated)
+		if inhibited > 0 {
+			text += fmt.Sprintf(", %d alert(s) would be inhibited by other alerts from this rule", inhibited)
+		}
+		if len(receivers) > 0 {
+			text += ", " + formatReceiverCounts(receivers)
+		}
+		if defaultOnly > 0 {
+			if c.defaultRoute {
+				severity = c.severity
+			}
+			text += fmt.Sprintf(", %d alert(s) would only match the default route", defaultOnly)
+		}
+	}
+
 	problems = append(problems, Problem{
 		Fragment: rule.AlertingRule.Expr.Value.Value,
 		Lines:    lines,
 		Reporter: AlertsCheckName,
-		Text:     fmt.Sprintf("query using %s would trigger %d alert(s) in the last %s", c.name, alerts, promapi.HumanizeDuration(delta)),
-		Severity: Information,
+		Text:     text,
+		Severity: severity,
 	})
 	return
 }
+
+type firingPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// firingPeriods returns all periods when the alert for a single series would
+// be firing, gaps longer than step between samples resolve the alert and
+// alerts only start firing after being pending for the for duration
+func (c AlertsCheck) firingPeriods(values []model.SamplePair, forDur time.Duration) (periods []firingPeriod) {
+	var isAlerting bool
+	var firstTime, lastTime time.Time
+	for _, value := range values {
+		ts := value.Timestamp.Time()
+		if ts.After(lastTime.Add(c.step)) {
+			isAlerting = false
+			firstTime = ts
+		}
+		if !isAlerting && !ts.Before(firstTime.Add(forDur)) {
+			isAlerting = true
+			periods = append(periods, firingPeriod{start: ts})
+		}
+		if isAlerting {
+			periods[len(periods)-1].end = ts
+		}
+		lastTime = ts
+	}
+	return periods
+}
+
+type simulatedAlert struct {
+	labels  map[string]string
+	periods []firingPeriod
+}
+
+// alertLabels returns labels of alerts generated from a series, labels set
+// on the rule take precedence over series labels, templated values are
+// rendered using series labels and the last value, if that fails then the
+// series label is kept
+func alertLabels(rule parser.Rule, metric model.Metric, values []model.SamplePair) map[string]string {
+	seriesLabels := map[string]string{}
+	for name, value := range metric {
+		if name != model.MetricNameLabel {
+			seriesLabels[string(name)] = string(value)
+		}
+	}
+	var value float64
+	if len(values) > 0 {
+		value = float64(values[len(values)-1].Value)
+	}
+
+	lset := map[string]string{}
+	for name, value := range seriesLabels {
+		lset[name] = value
+	}
+	if rule.AlertingRule.Labels != nil {
+		for _, item := range rule.AlertingRule.Labels.Items {
+			if !strings.Contains(item.Value.Value, "{{") {
+				lset[item.Key.Value] = item.Value.Value
+				continue
+			}
+			if v, err := expandTemplate(item.Key.Value, item.Value.Value, seriesLabels, value); err == nil {
+				lset[item.Key.Value] = v
+			}
+		}
+	}
+	lset[model.AlertNameLabel] = rule.AlertingRule.Alert.Value.Value
+	return lset
+}
+
+// routeAlerts sends all firing alerts through the Alertmanager routing tree
+// and returns the number of alerts per receiver, alerts are inhibited if
+// any other alert inhibiting them was already firing when they started, only
+// alerts generated by the same rule are taken into account
+func (c AlertsCheck) routeAlerts(alerts []simulatedAlert) (receivers map[string]int, inhibited, defaultOnly int) {
+	receivers = map[string]int{}
+	for i, alert := range alerts {
+		routes := c.am.Match(alert.labels)
+		for _, fp := range alert.periods {
+			if isInhibited(c.am, alerts, i, fp) {
+				inhibited++
+				continue
+			}
+			for _, route := range routes {
+				receivers[route.Receiver]++
+			}
+			if c.am.IsDefault(routes) {
+				defaultOnly++
+			}
+		}
+	}
+	return receivers, inhibited, defaultOnly
+}
+
+func isInhibited(am *alertmanager.Config, alerts []simulatedAlert, index int, fp firingPeriod) bool {
+	for i, other := range alerts {
+		if i == index || !am.Inhibits(other.labels, alerts[index].labels) {
+			continue
+		}
+		for _, ofp := range other.periods {
+			if !fp.start.Before(ofp.start) && !fp.start.After(ofp.end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatReceiverCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] == counts[names[j]] {
+			return names[i] < names[j]
+		}
+		return counts[names[i]] > counts[names[j]]
+	})
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%d sent to %q receiver", counts[name], name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatLabelCounts(name string, counts map[string]int) string {
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] == counts[values[j]] {
+			return values[i] < values[j]
+		}
+		return counts[values[i]] > counts[values[j]]
+	})
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprintf("%d with %s=%q", counts[value], name, value))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cloudflare/pint/internal/alertmanager"
 	"github.com/cloudflare/pint/internal/checks"
 
 	"github.com/rs/zerolog"
@@ -71,6 +73,27 @@ func TestAlertsCheck(t *testing.T) {
 				now.AddDate(0, 0, -1).Add(time.Minute*30).Unix(),
 			)
 			_, _ = w.Write([]byte(out))
+		case "/overlap/api/v1/query_range":
+			w.WriteHeader(200)
+			values := []string{}
+			for i := 0; i <= 10; i++ {
+				values = append(values, fmt.Sprintf(`[%d,"0"]`, now.AddDate(0, 0, -1).Add(time.Minute*time.Duration(i)).Unix()))
+			}
+			out := fmt.Sprintf(`{
+				"status":"success",
+				"data":{
+					"resultType":"matrix",
+					"result":[
+						{"metric":{"instance":"1","severity":"critical"},"values":[%s]},
+						{"metric":{"instance":"1","severity":"warning"},"values":[[%d,"0"],[%d,"0"]]}
+					]
+				}
+			}`,
+				strings.Join(values, ","),
+				now.AddDate(0, 0, -1).Add(time.Minute*2).Unix(),
+				now.AddDate(0, 0, -1).Add(time.Minute*3).Unix(),
+			)
+			_, _ = w.Write([]byte(out))
 		default:
 			w.WriteHeader(400)
 			w.Header().Set("Content-Type", "application/json")
@@ -79,21 +102,44 @@ func TestAlertsCheck(t *testing.T) {
 	}))
 	defer srv.Close()
 
+	am, err := alertmanager.Parse([]byte(`
+route:
+  receiver: default
+  routes:
+  - matchers: [ instance="1" ]
+    receiver: team-a
+    routes:
+    - match:
+        severity: critical
+      receiver: pager
+inhibit_rules:
+- source_matchers: [ severity="critical" ]
+  target_matchers: [ severity="warning" ]
+  equal: [ instance ]
+receivers:
+- name: default
+- name: team-a
+- name: pager
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	testCases := []checkTest{
 		{
 			description: "ignores recording rules",
 			content:     "- record: foo\n  expr: up == 0\n",
-			checker:     checks.NewAlertsCheck("prom", "http://localhost", time.Second*5, time.Hour*24, time.Minute, time.Minute*5),
+			checker:     checks.NewAlertsCheck("prom", "http://localhost", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, nil),
 		},
 		{
 			description: "ignores rules with syntax errors",
 			content:     "- alert: Foo Is Down\n  expr: sum(\n",
-			checker:     checks.NewAlertsCheck("prom", "http://localhost", time.Second*5, time.Hour*24, time.Minute, time.Minute*5),
+			checker:     checks.NewAlertsCheck("prom", "http://localhost", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, nil),
 		},
 		{
 			description: "bad request",
 			content:     content,
-			checker:     checks.NewAlertsCheck("prom", srv.URL+"/400/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5),
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/400/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, nil),
 			problems: []checks.Problem{
 				{
 					Fragment: `up{job="foo"} == 0`,
@@ -107,7 +153,7 @@ func TestAlertsCheck(t *testing.T) {
 		{
 			description: "empty response",
 			content:     content,
-			checker:     checks.NewAlertsCheck("prom", srv.URL+"/empty/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5),
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/empty/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, nil),
 			problems: []checks.Problem{
 				{
 					Fragment: `up{job="foo"} == 0`,
@@ -121,13 +167,13 @@ func TestAlertsCheck(t *testing.T) {
 		{
 			description: "multiple alerts",
 			content:     content,
-			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5),
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, nil),
 			problems: []checks.Problem{
 				{
 					Fragment: `up{job="foo"} == 0`,
 					Lines:    []int{2},
 					Reporter: "alerts/count",
-					Text:     "query using prom would trigger 7 alert(s) in the last 1d",
+					Text:     "query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m",
 					Severity: checks.Information,
 				},
 			},
@@ -135,13 +181,125 @@ func TestAlertsCheck(t *testing.T) {
 		{
 			description: "for: 10m",
 			content:     "- alert: Foo Is Down\n  for: 10m\n  expr: up{job=\"foo\"} == 0\n",
-			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute*6, time.Minute*10),
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute*6, time.Minute*10, nil, 0, 0, false, checks.Warning, nil),
 			problems: []checks.Problem{
 				{
 					Fragment: `up{job="foo"} == 0`,
 					Lines:    []int{2, 3},
 					Reporter: "alerts/count",
-					Text:     "query using prom would trigger 1 alert(s) in the last 1d",
+					Text:     "query using prom would trigger 1 alert(s) in the last 1d, median alert duration is 18m, longest alert lasted 18m",
+					Severity: checks.Information,
+				},
+			},
+		},
+		{
+			description: "invalid for",
+			content:     "- alert: Foo Is Down\n  for: 10 minutes\n  expr: up{job=\"foo\"} == 0\n",
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute*6, time.Minute*10, nil, 0, 0, false, checks.Warning, nil),
+			problems: []checks.Problem{
+				{
+					Fragment: "10 minutes",
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     `can't simulate alerts using prom, failed to parse for value: not a valid duration string: "10 minutes"`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "flapping alerts",
+			content:     content,
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*10, nil, 0, 0, false, checks.Warning, nil),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     "query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m, 4 alert(s) would resolve and fire again within 10m",
+					Severity: checks.Information,
+				},
+			},
+		},
+		{
+			description: "alerts by labels",
+			content:     "- alert: Foo Is Down\n  expr: up{job=\"foo\"} == 0\n  labels:\n    severity: critical\n",
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, []string{"instance", "severity", "cluster"}, 0, 0, false, checks.Warning, nil),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     `query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m, 5 with instance="2", 2 with instance="1", 7 with severity="critical", 7 with cluster=""`,
+					Severity: checks.Information,
+				},
+			},
+		},
+		{
+			description: "maxAlerts",
+			content:     content,
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 5, 0, false, checks.Bug, nil),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     "query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m, maximum allowed alerts is 5",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "maxFlapping",
+			content:     content,
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*10, nil, 10, 2, false, checks.Warning, nil),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     "query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m, 4 alert(s) would resolve and fire again within 10m, maximum allowed flapping alerts is 2",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "alerts routed to receivers",
+			content:     content,
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, am),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     `query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m, 5 sent to "default" receiver, 2 sent to "team-a" receiver, 5 alert(s) would only match the default route`,
+					Severity: checks.Information,
+				},
+			},
+		},
+		{
+			description: "templated labels are rendered before routing",
+			content:     content + "  labels:\n    severity: '{{ if eq $labels.instance \"1\" }}critical{{ else }}warning{{ end }}'\n",
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/alerts/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, true, checks.Warning, am),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     `query using prom would trigger 7 alert(s) in the last 1d, median alert duration is 1m, longest alert lasted 3m, 5 sent to "default" receiver, 2 sent to "pager" receiver, 5 alert(s) would only match the default route`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "inhibited alerts",
+			content:     content,
+			checker:     checks.NewAlertsCheck("prom", srv.URL+"/overlap/", time.Second*5, time.Hour*24, time.Minute, time.Minute*5, nil, 0, 0, false, checks.Warning, am),
+			problems: []checks.Problem{
+				{
+					Fragment: `up{job="foo"} == 0`,
+					Lines:    []int{2},
+					Reporter: "alerts/count",
+					Text:     `query using prom would trigger 2 alert(s) in the last 1d, median alert duration is 11m, longest alert lasted 11m, 1 alert(s) would be inhibited by other alerts from this rule, 1 sent to "pager" receiver`,
 					Severity: checks.Information,
 				},
 			},
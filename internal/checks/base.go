@@ -6,6 +6,13 @@ import (
 	"github.com/cloudflare/pint/internal/parser"
 )
 
+const (
+	// RemovedCheckName is the name used to report rules removed on a branch,
+	// it's not a rule checker since it needs git history, pint ci runs it
+	// directly
+	RemovedCheckName = "rule/removed"
+)
+
 var (
 	CheckNames []string = []string{
 		AlertsCheckName,
@@ -19,6 +26,17 @@ var (
 		SyntaxCheckName,
 		WithoutCheckName,
 		RejectCheckName,
+		GroupCheckName,
+		FormatCheckName,
+		TemplateCheckName,
+		DependencyCheckName,
+		DuplicateCheckName,
+		NamingCheckName,
+		CounterCheckName,
+		HistogramCheckName,
+		ForCheckName,
+		RoutingCheckName,
+		RemovedCheckName,
 	}
 )
 
@@ -73,6 +91,7 @@ type Problem struct {
 	Reporter string
 	Text     string
 	Severity Severity
+	Fix      *Fix
 }
 
 func (p Problem) LineRange() (int, int) {
@@ -84,8 +103,16 @@ type RuleChecker interface {
 	Check(rule parser.Rule) []Problem
 }
 
+// FileChecker is a check that runs once for every file instead of for
+// every rule in it
+type FileChecker interface {
+	String() string
+	CheckFile(content []byte, groups []parser.RuleGroup) []Problem
+}
+
 type exprProblem struct {
 	expr     string
 	text     string
 	severity Severity
+	fix      *Fix
 }
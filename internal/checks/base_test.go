@@ -34,6 +34,29 @@ func runTests(t *testing.T, testCases []checkTest, opts ...cmp.Option) {
 	}
 }
 
+type fileCheckTest struct {
+	description string
+	content     string
+	checker     checks.FileChecker
+	problems    []checks.Problem
+}
+
+func runFileTests(t *testing.T, testCases []fileCheckTest, opts ...cmp.Option) {
+	p := parser.NewParser()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, groups, err := p.ParseWithGroups([]byte(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			problems := tc.checker.CheckFile([]byte(tc.content), groups)
+			if diff := cmp.Diff(tc.problems, problems, opts...); diff != "" {
+				t.Errorf("CheckFile() returned wrong problem list (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestParseSeverity(t *testing.T) {
 	type testCaseT struct {
 		input       string
@@ -46,20 +46,21 @@ func (c ByCheck) Check(rule parser.Rule) (problems []Problem) {
 		}
 	}
 
-	for _, problem := range c.checkNode(expr.Query) {
+	for _, problem := range c.checkNode(expr, expr.Query) {
 		problems = append(problems, Problem{
 			Fragment: problem.expr,
 			Lines:    expr.Lines(),
 			Reporter: ByCheckName,
 			Text:     problem.text,
 			Severity: c.severity,
+			Fix:      problem.fix,
 		})
 	}
 
 	return
 }
 
-func (c ByCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem) {
+func (c ByCheck) checkNode(expr parser.PromQLExpr, node *parser.PromQLNode) (problems []exprProblem) {
 	if n, ok := node.Node.(*promParser.AggregateExpr); ok && !n.Without && n.Op != promParser.TOPK {
 		var found bool
 		for _, g := range n.Grouping {
@@ -73,6 +74,7 @@ func (c ByCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem) {
 			problems = append(problems, exprProblem{
 				expr: node.Expr,
 				text: fmt.Sprintf("%s label should be removed when aggregating %q rules, remove %s from by()", c.label, c.nameRegex, c.label),
+				fix:  groupingFix(expr, n, c.label, false),
 			})
 		}
 
@@ -80,12 +82,13 @@ func (c ByCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem) {
 			problems = append(problems, exprProblem{
 				expr: node.Expr,
 				text: fmt.Sprintf("%s label is required and should be preserved when aggregating %q rules, use by(%s, ...)", c.label, c.nameRegex, c.label),
+				fix:  groupingFix(expr, n, c.label, true),
 			})
 		}
 	}
 
 	for _, child := range node.Children {
-		problems = append(problems, c.checkNode(child)...)
+		problems = append(problems, c.checkNode(expr, child)...)
 	}
 
 	return
@@ -35,6 +35,38 @@ func TestByCheck(t *testing.T) {
 					Reporter: "promql/by",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "by(instance)",
+								New:       "by (instance, job)",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "no fix when fragment is split across lines",
+			content:     "- record: foo\n  expr:\n    sum by\n    (instance) (foo) / sum by (instance) (bar)\n",
+			checker:     checks.NewByCheck(regexp.MustCompile("^.+$"), "job", true, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum by(instance) (foo)",
+					Lines:    []int{2, 3, 4},
+					Reporter: "promql/by",
+					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "sum by(instance) (bar)",
+					Lines:    []int{2, 3, 4},
+					Reporter: "promql/by",
+					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
+					Severity: checks.Warning,
 				},
 			},
 		},
@@ -49,6 +81,17 @@ func TestByCheck(t *testing.T) {
 					Reporter: "promql/by",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "by(instance)",
+								New:       "by (instance, job)",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -63,6 +106,39 @@ func TestByCheck(t *testing.T) {
 					Reporter: "promql/by",
 					Text:     "job label should be removed when aggregating \"^.+$\" rules, remove job from by()",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       " by(job)",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "must strip job label / by() on its own line",
+			content:     "- record: foo\n  expr: |\n    sum(foo)\n    by(job)\n",
+			checker:     checks.NewByCheck(regexp.MustCompile("^.+$"), "job", false, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum(foo)\nby(job)\n",
+					Lines:    []int{2, 3, 4},
+					Reporter: "promql/by",
+					Text:     "job label should be removed when aggregating \"^.+$\" rules, remove job from by()",
+					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 4,
+								LastLine:  4,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -82,6 +158,102 @@ func TestByCheck(t *testing.T) {
 					Reporter: "promql/by",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "by(instance)",
+								New:       "by (instance, job)",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "must keep job label / no grouping",
+			content:     "- record: foo\n  expr: sum(foo{a=\"(\"}) + sum(bar)\n",
+			checker:     checks.NewByCheck(regexp.MustCompile("^.+$"), "job", true, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: `sum(foo{a="("})`,
+					Lines:    []int{2},
+					Reporter: "promql/by",
+					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
+					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       `sum(foo{a="("})`,
+								New:       `sum(foo{a="("}) by (job)`,
+							},
+						},
+					},
+				},
+				{
+					Fragment: "sum(bar)",
+					Lines:    []int{2},
+					Reporter: "promql/by",
+					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, use by(job, ...)",
+					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "sum(bar)",
+								New:       "sum(bar) by (job)",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "must strip job label / grouping before expression",
+			content:     "- record: foo\n  expr: sum by (job) (foo) / sum by (job) (bar)\n",
+			checker:     checks.NewByCheck(regexp.MustCompile("^.+$"), "job", false, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum by(job) (foo)",
+					Lines:    []int{2},
+					Reporter: "promql/by",
+					Text:     "job label should be removed when aggregating \"^.+$\" rules, remove job from by()",
+					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       " by (job)",
+							},
+						},
+					},
+				},
+				{
+					Fragment: "sum by(job) (bar)",
+					Lines:    []int{2},
+					Reporter: "promql/by",
+					Text:     "job label should be removed when aggregating \"^.+$\" rules, remove job from by()",
+					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       " by (job)",
+								Match:     1,
+							},
+						},
+					},
 				},
 			},
 		},
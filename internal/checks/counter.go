@@ -0,0 +1,246 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	CounterCheckName = "promql/counter"
+)
+
+var (
+	// functions that should only be used with counters
+	counterFuncs = map[string]bool{
+		"rate":     true,
+		"irate":    true,
+		"increase": true,
+	}
+
+	// functions that should only be used with gauges and the function
+	// that should be used with counters instead
+	gaugeFuncs = map[string]string{
+		"delta":          "increase",
+		"idelta":         "irate",
+		"deriv":          "rate",
+		"predict_linear": "",
+	}
+
+	// aggregations that don't use sample values, so it's fine to pass
+	// counters to them
+	valuelessAggregations = map[promParser.ItemType]bool{
+		promParser.COUNT:        true,
+		promParser.GROUP:        true,
+		promParser.COUNT_VALUES: true,
+	}
+
+	// suffixes used to guess the metric type when metadata is not available
+	counterSuffixes = []string{"_total", "_count", "_sum", "_bucket"}
+)
+
+// NewCounterCheck creates a check finding functions applied to metrics of
+// the wrong type, if uri is empty then metric types are only guessed from
+// metric names, otherwise metadata from given Prometheus server is used first
+func NewCounterCheck(name, uri string, timeout time.Duration, severity Severity) CounterCheck {
+	return CounterCheck{name: name, uri: uri, timeout: timeout, severity: severity}
+}
+
+// CounterCheck uses metric metadata to find functions applied to metrics of
+// the wrong type and counters used directly in alerting rules, metric names
+// are used to guess the type if there's no metadata for a metric
+type CounterCheck struct {
+	name     string
+	uri      string
+	timeout  time.Duration
+	severity Severity
+}
+
+func (c CounterCheck) String() string {
+	if c.uri == "" {
+		return CounterCheckName
+	}
+	return fmt.Sprintf("%s(%s)", CounterCheckName, c.name)
+}
+
+func (c CounterCheck) Check(rule parser.Rule) (problems []Problem) {
+	expr := rule.Expr()
+
+	if expr.SyntaxError != nil {
+		return
+	}
+
+	types := map[string]metricType{}
+	done := map[string]bool{}
+	for _, problem := range c.checkFuncs(expr.Query, types) {
+		if done[problem.text] {
+			continue
+		}
+		done[problem.text] = true
+		problems = append(problems, Problem{
+			Fragment: problem.expr,
+			Lines:    expr.Lines(),
+			Reporter: CounterCheckName,
+			Text:     problem.text,
+			Severity: problem.severity,
+		})
+	}
+
+	if rule.AlertingRule != nil {
+		for _, problem := range c.checkBare(expr.Query, types) {
+			if done[problem.text] {
+				continue
+			}
+			done[problem.text] = true
+			problems = append(problems, Problem{
+				Fragment: problem.expr,
+				Lines:    expr.Lines(),
+				Reporter: CounterCheckName,
+				Text:     problem.text,
+				Severity: problem.severity,
+			})
+		}
+	}
+
+	return
+}
+
+func (c CounterCheck) checkFuncs(node *parser.PromQLNode, types map[string]metricType) (problems []exprProblem) {
+	if n, ok := node.Node.(*promParser.Call); ok {
+		for _, arg := range n.Args {
+			m, ok := arg.(*promParser.MatrixSelector)
+			if !ok {
+				continue
+			}
+			vs, ok := m.VectorSelector.(*promParser.VectorSelector)
+			if !ok || vs.Name == "" {
+				continue
+			}
+			mt := c.getMetricType(vs.Name, types)
+			if mt.err != nil {
+				problems = append(problems, c.metadataProblem(node.Expr, vs.Name, mt.err))
+			}
+
+			if counterFuncs[n.Func.Name] && mt.kind == v1.MetricTypeGauge {
+				problems = append(problems, exprProblem{
+					expr:     node.Expr,
+					text:     fmt.Sprintf("%s() should only be used with counters but %s is a gauge %s", n.Func.Name, vs.Name, mt.source),
+					severity: c.severity,
+				})
+			}
+
+			if alt, ok := gaugeFuncs[n.Func.Name]; ok && mt.kind == v1.MetricTypeCounter {
+				text := fmt.Sprintf("%s() should only be used with gauges but %s is a counter %s", n.Func.Name, vs.Name, mt.source)
+				if alt != "" {
+					text = fmt.Sprintf("%s, use %s() instead", text, alt)
+				}
+				problems = append(problems, exprProblem{
+					expr:     node.Expr,
+					text:     text,
+					severity: c.severity,
+				})
+			}
+		}
+	}
+
+	for _, child := range node.Children {
+		problems = append(problems, c.checkFuncs(child, types)...)
+	}
+
+	return
+}
+
+// checkBare finds counters that are not passed to any function, so the
+// alert would be using the total number of events since the counter was
+// created instead of the number of recent events
+func (c CounterCheck) checkBare(node *parser.PromQLNode, types map[string]metricType) (problems []exprProblem) {
+	switch n := node.Node.(type) {
+	case *promParser.Call:
+		return nil
+	case *promParser.AggregateExpr:
+		if valuelessAggregations[n.Op] {
+			return nil
+		}
+	case *promParser.VectorSelector:
+		if n.Name == "" {
+			return nil
+		}
+		mt := c.getMetricType(n.Name, types)
+		if mt.err != nil {
+			problems = append(problems, c.metadataProblem(node.Expr, n.Name, mt.err))
+		}
+		if mt.kind == v1.MetricTypeCounter {
+			problems = append(problems, exprProblem{
+				expr:     node.Expr,
+				text:     fmt.Sprintf("%s is a counter %s, alerts using it without rate() or increase() will use the total value accumulated since the counter was created", n.Name, mt.source),
+				severity: c.severity,
+			})
+		}
+		return problems
+	}
+
+	for _, child := range node.Children {
+		problems = append(problems, c.checkBare(child, types)...)
+	}
+
+	return
+}
+
+type metricType struct {
+	kind   v1.MetricType
+	source string
+	err    error
+}
+
+func (c CounterCheck) metadataProblem(expr, name string, err error) exprProblem {
+	return exprProblem{
+		expr:     expr,
+		text:     fmt.Sprintf("failed to query %s prometheus metric metadata for %s: %s", c.name, name, err),
+		severity: Bug,
+	}
+}
+
+func (c CounterCheck) getMetricType(name string, types map[string]metricType) metricType {
+	if mt, ok := types[name]; ok {
+		return mt
+	}
+	mt := c.lookupMetricType(name)
+	types[name] = mt
+	return mt
+}
+
+func (c CounterCheck) lookupMetricType(name string) metricType {
+	// recording rules don't have any metadata and their names don't follow
+	// the same conventions as raw metrics
+	if strings.Contains(name, ":") {
+		return metricType{kind: v1.MetricTypeUnknown}
+	}
+
+	var err error
+	if c.uri != "" {
+		var metadata *promapi.MetadataResult
+		metadata, err = promapi.Metadata(c.uri, c.timeout, name)
+		if err == nil && len(metadata.Metadata) > 0 {
+			switch metadata.Metadata[0].Type {
+			case v1.MetricTypeCounter, v1.MetricTypeGauge:
+				return metricType{
+					kind:   metadata.Metadata[0].Type,
+					source: fmt.Sprintf("according to %s metadata", c.name),
+				}
+			}
+		}
+	}
+
+	for _, suffix := range counterSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return metricType{kind: v1.MetricTypeCounter, source: "based on its name", err: err}
+		}
+	}
+	return metricType{kind: v1.MetricTypeUnknown, err: err}
+}
@@ -0,0 +1,206 @@
+package checks_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/rs/zerolog"
+)
+
+func TestCounterCheck(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata/api/v1/metadata":
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("metric") {
+			case "errors":
+				_, _ = w.Write([]byte(`{"status":"success","data":{"errors":[{"type":"counter","help":"Errors","unit":""}]}}`))
+			case "temperature", "queue_total":
+				_, _ = w.Write([]byte(`{"status":"success","data":{"` + r.URL.Query().Get("metric") + `":[{"type":"gauge","help":"Gauge","unit":""}]}}`))
+			default:
+				_, _ = w.Write([]byte(`{"status":"success","data":{}}`))
+			}
+		case "/error/api/v1/metadata":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		default:
+			w.WriteHeader(400)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled path"}`))
+		}
+	}))
+	defer srv.Close()
+
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+		},
+		{
+			description: "rate on counter",
+			content:     "- record: foo\n  expr: rate(errors[5m])\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+		},
+		{
+			description: "rate on gauge",
+			content:     "- record: foo\n  expr: sum(rate(temperature[5m]))\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "rate(temperature[5m])",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "rate() should only be used with counters but temperature is a gauge according to prom metadata",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "metadata takes precedence over metric name",
+			content:     "- record: foo\n  expr: increase(queue_total[5m])\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Bug),
+			problems: []checks.Problem{
+				{
+					Fragment: "increase(queue_total[5m])",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "increase() should only be used with counters but queue_total is a gauge according to prom metadata",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "delta on counter",
+			content:     "- record: foo\n  expr: delta(errors[5m])\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "delta(errors[5m])",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "delta() should only be used with gauges but errors is a counter according to prom metadata, use increase() instead",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "deriv on counter without metadata",
+			content:     "- record: foo\n  expr: deriv(requests_total[5m])\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "deriv(requests_total[5m])",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "deriv() should only be used with gauges but requests_total is a counter based on its name, use rate() instead",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "predict_linear on counter when metadata query fails",
+			content:     "- record: foo\n  expr: predict_linear(request_duration_seconds_sum[5m], 3600)\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/error/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "predict_linear(request_duration_seconds_sum[5m], 3600)",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "failed to query prom prometheus metric metadata for request_duration_seconds_sum: failed to query Prometheus metric metadata: server_error: server error: 500",
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "predict_linear(request_duration_seconds_sum[5m], 3600)",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "predict_linear() should only be used with gauges but request_duration_seconds_sum is a counter based on its name",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "offline check uses metric names",
+			content:     "- alert: foo\n  expr: rate(temperature[5m]) > 0 or delta(errors_total[5m]) > 0 or errors_total > 0\n",
+			checker:     checks.NewCounterCheck("", "", 0, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "delta(errors_total[5m])",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "delta() should only be used with gauges but errors_total is a counter based on its name, use increase() instead",
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "errors_total",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "errors_total is a counter based on its name, alerts using it without rate() or increase() will use the total value accumulated since the counter was created",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "delta on gauge",
+			content:     "- record: foo\n  expr: delta(temperature[5m])\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+		},
+		{
+			description: "bare counter in recording rule",
+			content:     "- record: foo\n  expr: sum(errors)\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+		},
+		{
+			description: "bare counter in alerting rule",
+			content:     "- alert: foo\n  expr: sum(errors) > 0\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "errors",
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "errors is a counter according to prom metadata, alerts using it without rate() or increase() will use the total value accumulated since the counter was created",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "bare counter in alerting rule without metadata",
+			content:     "- alert: foo\n  expr: http_requests_total{code=\"500\"} > 10 and http_requests_total{code=\"500\"} < 100\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/error/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: `http_requests_total{code="500"}`,
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "failed to query prom prometheus metric metadata for http_requests_total: failed to query Prometheus metric metadata: server_error: server error: 500",
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: `http_requests_total{code="500"}`,
+					Lines:    []int{2},
+					Reporter: "promql/counter",
+					Text:     "http_requests_total is a counter based on its name, alerts using it without rate() or increase() will use the total value accumulated since the counter was created",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "counters passed to functions or counted in alerting rule",
+			content:     "- alert: foo\n  expr: absent(errors) or count(http_requests_total) == 0 or sum(rate(errors[5m])) > 0 or resets(errors[1h]) > 0\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+		},
+		{
+			description: "ignores recording rules and gauges in alerting rule",
+			content:     "- alert: foo\n  expr: job:errors_total:sum > 0 or temperature > 50 or queue_total > 5\n",
+			checker:     checks.NewCounterCheck("prom", srv.URL+"/metadata/", time.Second, checks.Warning),
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,60 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	DependencyCheckName = "rule/dependency"
+)
+
+func NewDependencyCheck(g *graph.Graph, severity Severity) DependencyCheck {
+	return DependencyCheck{graph: g, severity: severity}
+}
+
+// DependencyCheck uses a graph of all scanned rules to find queries
+// using recording rules that are not defined anywhere and recording rules
+// that depend on themselves
+type DependencyCheck struct {
+	graph    *graph.Graph
+	severity Severity
+}
+
+func (c DependencyCheck) String() string {
+	return DependencyCheckName
+}
+
+func (c DependencyCheck) Check(rule parser.Rule) (problems []Problem) {
+	expr := rule.Expr()
+	if expr.SyntaxError != nil {
+		return nil
+	}
+
+	for _, name := range c.graph.Missing(rule) {
+		problems = append(problems, Problem{
+			Fragment: name,
+			Lines:    expr.Lines(),
+			Reporter: DependencyCheckName,
+			Text:     fmt.Sprintf("%s looks like a recording rule but it's not defined in any of the scanned files", name),
+			Severity: c.severity,
+		})
+	}
+
+	if rule.RecordingRule != nil {
+		if cycle := c.graph.Cycle(rule.Name()); cycle != nil {
+			problems = append(problems, Problem{
+				Fragment: rule.Name(),
+				Lines:    expr.Lines(),
+				Reporter: DependencyCheckName,
+				Text:     fmt.Sprintf("recording rule is part of a dependency cycle: %s", strings.Join(cycle, " -> ")),
+				Severity: Bug,
+			})
+		}
+	}
+
+	return problems
+}
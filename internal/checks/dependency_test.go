@@ -0,0 +1,120 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDependencyCheck(t *testing.T) {
+	type testCaseT struct {
+		description string
+		content     string
+		severity    checks.Severity
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "recording rule is defined",
+			content: `- record: job:up:sum
+  expr: sum(up) by(job)
+- alert: Job Is Down
+  expr: job:up:sum == 0
+`,
+		},
+		{
+			description: "plain metrics are ignored",
+			content: `- alert: Job Is Down
+  expr: up == 0
+`,
+		},
+		{
+			description: "recording rule is missing",
+			content: `- record: job:up:sum
+  expr: sum(up) by(job)
+- alert: Job Is Down
+  expr: job:up:sum == 0 or job:up:count == 0
+`,
+			severity: checks.Warning,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:up:count",
+					Lines:    []int{4},
+					Reporter: "rule/dependency",
+					Text:     "job:up:count looks like a recording rule but it's not defined in any of the scanned files",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "missing recording rule with custom severity",
+			content: `- alert: Job Is Down
+  expr: job:up:count == 0
+`,
+			severity: checks.Information,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:up:count",
+					Lines:    []int{2},
+					Reporter: "rule/dependency",
+					Text:     "job:up:count looks like a recording rule but it's not defined in any of the scanned files",
+					Severity: checks.Information,
+				},
+			},
+		},
+		{
+			description: "dependency cycle",
+			content: `- record: foo:a
+  expr: foo:b
+- record: foo:b
+  expr: sum(foo:a)
+- record: foo:c
+  expr: foo:a
+`,
+			severity: checks.Information,
+			problems: []checks.Problem{
+				{
+					Fragment: "foo:a",
+					Lines:    []int{2},
+					Reporter: "rule/dependency",
+					Text:     "recording rule is part of a dependency cycle: foo:a -> foo:b -> foo:a",
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "foo:b",
+					Lines:    []int{4},
+					Reporter: "rule/dependency",
+					Text:     "recording rule is part of a dependency cycle: foo:b -> foo:a -> foo:b",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+
+	p := parser.NewParser()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			rules, err := p.Parse([]byte(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries := []graph.Entry{}
+			for _, rule := range rules {
+				entries = append(entries, graph.Entry{Path: "rules.yml", Rule: rule})
+			}
+			checker := checks.NewDependencyCheck(graph.New(entries), tc.severity)
+			var problems []checks.Problem
+			for _, rule := range rules {
+				problems = append(problems, checker.Check(rule)...)
+			}
+			if diff := cmp.Diff(tc.problems, problems); diff != "" {
+				t.Errorf("Check() returned wrong problem list (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	DuplicateCheckName = "rule/duplicate"
+)
+
+func NewDuplicateCheck(g *graph.Graph, path string, severity Severity) DuplicateCheck {
+	return DuplicateCheck{graph: g, path: path, severity: severity}
+}
+
+// DuplicateCheck finds rules that are defined more than once in all scanned
+// files, path is the file with rules passed to Check(), every pair of
+// duplicated rules is only reported on the one defined later
+type DuplicateCheck struct {
+	graph    *graph.Graph
+	path     string
+	severity Severity
+}
+
+func (c DuplicateCheck) String() string {
+	return DuplicateCheckName
+}
+
+func (c DuplicateCheck) Check(rule parser.Rule) (problems []Problem) {
+	line := ruleNameLine(rule)
+	for _, other := range c.graph.Definitions(rule) {
+		otherLine := ruleNameLine(other.Rule)
+		if other.Path > c.path || (other.Path == c.path && otherLine >= line) {
+			continue
+		}
+
+		var text string
+		switch {
+		case rule.RecordingRule != nil && other.Rule.RecordingRule != nil:
+			if staticLabels(rule.RecordingRule.Labels) != staticLabels(other.Rule.RecordingRule.Labels) {
+				continue
+			}
+			text = fmt.Sprintf("duplicated recording rule, %s:%d has the same name and labels, both rules will produce conflicting series", other.Path, otherLine)
+		case rule.AlertingRule != nil && other.Rule.AlertingRule != nil:
+			if staticLabels(rule.AlertingRule.Labels) != staticLabels(other.Rule.AlertingRule.Labels) ||
+				rule.AlertingRule.Expr.Normalized() != other.Rule.AlertingRule.Expr.Normalized() {
+				continue
+			}
+			text = fmt.Sprintf("duplicated alerting rule, %s:%d has the same name, query and labels", other.Path, otherLine)
+		default:
+			continue
+		}
+
+		problems = append(problems, Problem{
+			Fragment: rule.Name(),
+			Lines:    rule.Lines(),
+			Reporter: DuplicateCheckName,
+			Text:     text,
+			Severity: c.severity,
+		})
+	}
+	return problems
+}
+
+func ruleNameLine(rule parser.Rule) int {
+	if rule.RecordingRule != nil {
+		return rule.RecordingRule.Record.Key.Position.FistLine()
+	}
+	return rule.AlertingRule.Alert.Key.Position.FistLine()
+}
+
+// staticLabels returns labels as a sorted string that can be compared
+func staticLabels(m *parser.YamlMap) string {
+	if m == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(m.Items))
+	for _, item := range m.Items {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", item.Key.Value, item.Value.Value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
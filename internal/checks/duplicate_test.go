@@ -0,0 +1,149 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDuplicateCheck(t *testing.T) {
+	type testCaseT struct {
+		description string
+		files       map[string]string
+		path        string
+		severity    checks.Severity
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no duplicates",
+			files: map[string]string{
+				"a.yml": "- record: foo\n  expr: sum(bar)\n",
+				"b.yml": "- record: bar\n  expr: sum(foo)\n",
+			},
+			path: "a.yml",
+		},
+		{
+			description: "same recording rule with different labels",
+			files: map[string]string{
+				"a.yml": "- record: foo\n  expr: sum(bar)\n  labels:\n    cluster: a\n",
+				"b.yml": "- record: foo\n  expr: sum(bar)\n  labels:\n    cluster: b\n",
+			},
+			path: "a.yml",
+		},
+		{
+			description: "duplicated recording rule",
+			files: map[string]string{
+				"a.yml": "- record: foo\n  expr: sum(bar)\n  labels:\n    cluster: a\n    env: prod\n",
+				"b.yml": "- record: bar\n  expr: sum(bar)\n- record: foo\n  expr: sum(bar) by(job)\n  labels:\n    env: prod\n    cluster: a\n",
+			},
+			path:     "b.yml",
+			severity: checks.Bug,
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{3, 4, 5, 6, 7},
+					Reporter: "rule/duplicate",
+					Text:     "duplicated recording rule, a.yml:1 has the same name and labels, both rules will produce conflicting series",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "duplicated recording rule is only reported on the later copy",
+			files: map[string]string{
+				"a.yml": "- record: foo\n  expr: sum(bar)\n  labels:\n    cluster: a\n    env: prod\n",
+				"b.yml": "- record: bar\n  expr: sum(bar)\n- record: foo\n  expr: sum(bar) by(job)\n  labels:\n    env: prod\n    cluster: a\n",
+			},
+			path: "a.yml",
+		},
+		{
+			description: "duplicated recording rule in the same file",
+			files: map[string]string{
+				"a.yml": "- record: foo\n  expr: sum(bar)\n- record: foo\n  expr: sum(bar)\n- record: foo\n  expr: sum(bar)\n",
+			},
+			path:     "a.yml",
+			severity: checks.Bug,
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{3, 4},
+					Reporter: "rule/duplicate",
+					Text:     "duplicated recording rule, a.yml:1 has the same name and labels, both rules will produce conflicting series",
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "foo",
+					Lines:    []int{5, 6},
+					Reporter: "rule/duplicate",
+					Text:     "duplicated recording rule, a.yml:1 has the same name and labels, both rules will produce conflicting series",
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "foo",
+					Lines:    []int{5, 6},
+					Reporter: "rule/duplicate",
+					Text:     "duplicated recording rule, a.yml:3 has the same name and labels, both rules will produce conflicting series",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "alerts with different queries",
+			files: map[string]string{
+				"a.yml": "- alert: foo\n  expr: up == 0\n",
+				"b.yml": "- alert: foo\n  expr: up{job=\"foo\"} == 0\n",
+			},
+			path: "a.yml",
+		},
+		{
+			description: "duplicated alerting rule",
+			files: map[string]string{
+				"a.yml": "- alert: foo\n  expr: up == 0\n  annotations:\n    summary: foo\n",
+				"b.yml": "- alert: foo\n  expr: up   ==   0\n  annotations:\n    summary: bar\n",
+			},
+			path:     "b.yml",
+			severity: checks.Warning,
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{1, 2, 3, 4},
+					Reporter: "rule/duplicate",
+					Text:     "duplicated alerting rule, a.yml:1 has the same name, query and labels",
+					Severity: checks.Warning,
+				},
+			},
+		},
+	}
+
+	p := parser.NewParser()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entries := []graph.Entry{}
+			for _, path := range []string{"a.yml", "b.yml"} {
+				rules, err := p.Parse([]byte(tc.files[path]))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, rule := range rules {
+					entries = append(entries, graph.Entry{Path: path, Rule: rule})
+				}
+			}
+			checker := checks.NewDuplicateCheck(graph.New(entries), tc.path, tc.severity)
+			var problems []checks.Problem
+			for _, entry := range entries {
+				if entry.Path == tc.path {
+					problems = append(problems, checker.Check(entry.Rule)...)
+				}
+			}
+			if diff := cmp.Diff(tc.problems, problems); diff != "" {
+				t.Errorf("Check() returned wrong problem list (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
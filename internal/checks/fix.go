@@ -0,0 +1,282 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/common/model"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+// EditOp is the type of change made by a single Edit
+type EditOp int
+
+const (
+	// ReplaceText replaces Old with New in lines FirstLine to LastLine
+	ReplaceText EditOp = iota
+
+	// DeleteLines removes lines FirstLine to LastLine
+	DeleteLines
+
+	// InsertLines adds New as new lines after Target line, every line will
+	// be indented the same way as IndentFrom line
+	InsertLines
+
+	// CopyLines inserts a copy of lines FirstLine to LastLine after Target line,
+	// if IndentFrom is set then copied lines are re-indented so that FirstLine
+	// has the same indentation as IndentFrom line
+	CopyLines
+)
+
+// Edit is a single change to the rule file, all line numbers refer
+// to the file content as it was when checks were run
+type Edit struct {
+	Op         EditOp
+	FirstLine  int
+	LastLine   int
+	Old        string
+	New        string
+	Match      int // index of the Old occurrence to replace, 0 is the first one
+	Target     int
+	IndentFrom int
+}
+
+// Fix is a suggested change that will resolve reported problem
+type Fix struct {
+	Edits []Edit
+}
+
+func lineRange(lines []int) (first, last int) {
+	for _, l := range lines {
+		if first == 0 || l < first {
+			first = l
+		}
+		if l > last {
+			last = l
+		}
+	}
+	return
+}
+
+// replaceInExpr returns a fix that will replace expr[start:end] with text,
+// it can only be used when replaced fragment is on a single line.
+// Fixes are applied to the file content, which can be different from the
+// decoded expression, so no fix is returned unless every occurrence of the
+// replaced fragment in the expression can also be found in the file
+func replaceInExpr(expr parser.PromQLExpr, start, end int, text string) *Fix {
+	query := expr.Value.Value
+	if start < 0 || end > len(query) || start > end {
+		return nil
+	}
+	old := query[start:end]
+	if old == "" || strings.Contains(old, "\n") {
+		return nil
+	}
+	if expr.SourceOffset > len(expr.Source) {
+		return nil
+	}
+	if strings.Count(expr.Source[expr.SourceOffset:], old) != strings.Count(query, old) {
+		return nil
+	}
+	first, last := lineRange(expr.Lines())
+	return &Fix{
+		Edits: []Edit{
+			{
+				Op:        ReplaceText,
+				FirstLine: first,
+				LastLine:  last,
+				Old:       old,
+				New:       text,
+				Match:     strings.Count(expr.Source[:expr.SourceOffset], old) + strings.Count(query[:start], old),
+			},
+		},
+	}
+}
+
+// groupingFix returns a fix that will add or remove label from
+// the by() or without() clause of an aggregation
+func groupingFix(expr parser.PromQLExpr, n *promParser.AggregateExpr, label string, add bool) *Fix {
+	query := expr.Value.Value
+	pos := n.PositionRange()
+	if int(pos.End) > len(query) {
+		return nil
+	}
+	start, end, keyword := findGrouping(query, int(pos.Start), int(pos.End))
+
+	grouping := []string{}
+	for _, g := range n.Grouping {
+		if g != label {
+			grouping = append(grouping, g)
+		}
+	}
+	if add {
+		grouping = append(grouping, label)
+	}
+
+	if start < 0 {
+		// there's no grouping clause so it must be a by() we need to add
+		if !add || n.Without {
+			return nil
+		}
+		agg := query[pos.Start:pos.End]
+		return replaceInExpr(expr, int(pos.Start), int(pos.End), fmt.Sprintf("%s by (%s)", agg, label))
+	}
+
+	if len(grouping) == 0 && !n.Without {
+		// by() with no labels is the same as no by() at all, remove it
+		// together with whitespace that separates it from the rest
+		if fix := deleteExprLine(expr, start, end); fix != nil {
+			return fix
+		}
+		for start > int(pos.Start) && query[start-1] == ' ' {
+			start--
+		}
+		if query[start] != ' ' {
+			for end < int(pos.End) && query[end] == ' ' {
+				end++
+			}
+		}
+		return replaceInExpr(expr, start, end, "")
+	}
+
+	return replaceInExpr(expr, start, end, fmt.Sprintf("%s (%s)", keyword, strings.Join(grouping, ", ")))
+}
+
+// deleteExprLine returns a fix that will remove the whole line with
+// expr[start:end] if that fragment is the only thing on that line of a block
+// scalar, otherwise it returns nil
+func deleteExprLine(expr parser.PromQLExpr, start, end int) *Fix {
+	query := expr.Value.Value
+	if (start > 0 && query[start-1] != '\n') || (end < len(query) && query[end] != '\n') {
+		return nil
+	}
+	if expr.SourceOffset > len(expr.Source) {
+		return nil
+	}
+	idx := strings.Count(query[:start], "\n")
+	lines := strings.SplitAfter(expr.Source[expr.SourceOffset:], "\n")
+	if idx >= len(lines) || strings.TrimSpace(lines[idx]) != query[start:end] {
+		return nil
+	}
+	first, _ := lineRange(expr.Value.Position.Lines)
+	return &Fix{
+		Edits: []Edit{
+			{
+				Op:        DeleteLines,
+				FirstLine: first + idx,
+				LastLine:  first + idx,
+			},
+		},
+	}
+}
+
+// findGrouping returns the position of by() or without() clause of the
+// aggregation found at query[start:end], start will be -1 if there's none
+func findGrouping(query string, start, end int) (int, int, string) {
+	i := start
+	// skip aggregation operator
+	for i < end && isIdentChar(query[i]) {
+		i++
+	}
+	i = skipSpaces(query, i, end)
+
+	if s, e, kw := groupingAt(query, i, end); s >= 0 {
+		return s, e, kw
+	}
+
+	// skip aggregation body to check if there's a grouping after it
+	if i >= end || query[i] != '(' {
+		return -1, -1, ""
+	}
+	i = matchingParen(query, i, end)
+	if i < 0 {
+		return -1, -1, ""
+	}
+	return groupingAt(query, skipSpaces(query, i+1, end), end)
+}
+
+func groupingAt(query string, i, end int) (int, int, string) {
+	j := i
+	for j < end && isIdentChar(query[j]) {
+		j++
+	}
+	keyword := query[i:j]
+	switch strings.ToLower(keyword) {
+	case "by", "without":
+	default:
+		return -1, -1, ""
+	}
+
+	k := skipSpaces(query, j, end)
+	if k >= end || query[k] != '(' {
+		return -1, -1, ""
+	}
+	k = matchingParen(query, k, end)
+	if k < 0 {
+		return -1, -1, ""
+	}
+	return i, k + 1, keyword
+}
+
+// matchingParen returns the index of the closing parenthesis matching
+// the one at query[i], ignoring any parenthesis inside quoted strings
+func matchingParen(query string, i, end int) int {
+	var depth int
+	var quote byte
+	for ; i < end; i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func skipSpaces(query string, i, end int) int {
+	for i < end && (query[i] == ' ' || query[i] == '\t' || query[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// rangeFix returns a fix that will change the range of a matrix selector
+func rangeFix(expr parser.PromQLExpr, m *promParser.MatrixSelector, d time.Duration) *Fix {
+	query := expr.Value.Value
+	vs := m.VectorSelector.PositionRange()
+	if int(m.EndPos) > len(query) || vs.End > m.EndPos {
+		return nil
+	}
+	// range is the first [...] after the selector, it can be followed by an offset
+	start := strings.IndexByte(query[vs.End:m.EndPos], '[')
+	if start < 0 {
+		return nil
+	}
+	start += int(vs.End)
+	end := strings.IndexByte(query[start:m.EndPos], ']')
+	if end < 0 {
+		return nil
+	}
+	end += start + 1
+	return replaceInExpr(expr, start, end, fmt.Sprintf("[%s]", model.Duration(d)))
+}
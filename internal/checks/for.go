@@ -0,0 +1,176 @@
+package checks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+
+	"github.com/prometheus/common/model"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	ForCheckName = "alerts/for"
+)
+
+var (
+	// functions calculating per-second values from samples in a range
+	windowFuncs = map[string]bool{
+		"rate":     true,
+		"irate":    true,
+		"increase": true,
+	}
+)
+
+// NewForValueCheck creates a check that only reports for values Prometheus
+// can't parse, it doesn't need any configuration so it's always enabled
+func NewForValueCheck() ForCheck {
+	return ForCheck{}
+}
+
+// NewForCheck creates a check validating alert for values, if uri is empty
+// then the check will compare them with the interval of the rule group and
+// with rate() windows used in the query, otherwise it will only compare them
+// with intervals from the Prometheus configuration
+func NewForCheck(name, uri string, timeout time.Duration, severity Severity) ForCheck {
+	return ForCheck{name: name, uri: uri, timeout: timeout, severity: severity, compare: true}
+}
+
+type ForCheck struct {
+	name     string
+	uri      string
+	timeout  time.Duration
+	severity Severity
+	compare  bool
+}
+
+func (c ForCheck) String() string {
+	if c.uri == "" {
+		return ForCheckName
+	}
+	return fmt.Sprintf("%s(%s)", ForCheckName, c.name)
+}
+
+func (c ForCheck) Check(rule parser.Rule) (problems []Problem) {
+	if rule.AlertingRule == nil || rule.AlertingRule.For == nil {
+		return
+	}
+
+	forVal := rule.AlertingRule.For.Value.Value
+	forDur, err := model.ParseDuration(forVal)
+	if err != nil {
+		if !c.compare {
+			problems = append(problems, c.problem(rule, fmt.Sprintf("invalid for value %q: %s", forVal, err), Fatal))
+		}
+		return
+	}
+	if forDur == 0 || !c.compare {
+		return
+	}
+
+	if c.uri == "" {
+		if rule.AlertingRule.Expr.SyntaxError == nil {
+			problems = append(problems, c.checkWindows(rule, time.Duration(forDur))...)
+		}
+		if rule.Group == nil || rule.Group.Interval == nil {
+			return
+		}
+		interval, err := model.ParseDuration(rule.Group.Interval.Value.Value)
+		if err != nil || interval == 0 {
+			return
+		}
+		return append(problems, c.checkInterval(rule, time.Duration(forDur), time.Duration(interval), "rule group interval")...)
+	}
+
+	cfg, err := promapi.Config(c.uri, c.timeout)
+	if err != nil {
+		problems = append(problems, c.problem(rule, fmt.Sprintf("failed to query %s prometheus config: %s", c.name, err), Bug))
+		return
+	}
+
+	// group interval takes precedence over evaluation_interval and it's
+	// already compared with for value by the check without uri
+	if rule.Group == nil || rule.Group.Interval == nil {
+		problems = append(problems, c.checkInterval(rule, time.Duration(forDur), cfg.Global.EvaluationInterval,
+			fmt.Sprintf("%s evaluation_interval", c.name))...)
+	}
+
+	if time.Duration(forDur) < cfg.Global.ScrapeInterval {
+		problems = append(problems, c.problem(rule, fmt.Sprintf(
+			"for: %s is shorter than %s scrape_interval of %s, alert might fire and resolve on every scrape",
+			forVal, c.name, promapi.HumanizeDuration(cfg.Global.ScrapeInterval)), c.severity))
+	}
+
+	return problems
+}
+
+func (c ForCheck) checkInterval(rule parser.Rule, forDur, interval time.Duration, source string) (problems []Problem) {
+	if interval == 0 {
+		return nil
+	}
+	forVal := rule.AlertingRule.For.Value.Value
+	switch {
+	case forDur < interval:
+		problems = append(problems, c.problem(rule, fmt.Sprintf(
+			"for: %s is shorter than %s of %s, alerts will always be pending for at least one evaluation interval",
+			forVal, source, promapi.HumanizeDuration(interval)), c.severity))
+	case forDur%interval != 0:
+		effective := (forDur/interval + 1) * interval
+		problems = append(problems, c.problem(rule, fmt.Sprintf(
+			"for: %s is not a multiple of %s of %s, alerts will fire after being pending for %s",
+			forVal, source, promapi.HumanizeDuration(interval), promapi.HumanizeDuration(effective)), c.severity))
+	}
+	return problems
+}
+
+// checkWindows reports for values shorter than rate() windows, a single
+// spike stays in the window for longer than the alert needs to fire, so
+// the alert can resolve and fire again while it's still in there
+func (c ForCheck) checkWindows(rule parser.Rule, forDur time.Duration) (problems []Problem) {
+	forVal := rule.AlertingRule.For.Value.Value
+	done := map[string]bool{}
+	for _, w := range rangeWindows(rule.AlertingRule.Expr.Query) {
+		if forDur >= w.window || done[w.expr] {
+			continue
+		}
+		done[w.expr] = true
+		problems = append(problems, c.problem(rule, fmt.Sprintf(
+			"for: %s is shorter than %s() window of %s used in %s, alert might fire and resolve multiple times for a single spike",
+			forVal, w.fn, promapi.HumanizeDuration(w.window), w.expr), c.severity))
+	}
+	return problems
+}
+
+func (c ForCheck) problem(rule parser.Rule, text string, severity Severity) Problem {
+	return Problem{
+		Fragment: fmt.Sprintf("for: %s", rule.AlertingRule.For.Value.Value),
+		Lines:    rule.AlertingRule.For.Lines(),
+		Reporter: ForCheckName,
+		Text:     text,
+		Severity: severity,
+	}
+}
+
+type rangeWindow struct {
+	expr   string
+	fn     string
+	window time.Duration
+}
+
+// rangeWindows returns all range vectors passed to rate(), irate() and
+// increase() in given query
+func rangeWindows(node *parser.PromQLNode) (windows []rangeWindow) {
+	if n, ok := node.Node.(*promParser.Call); ok && windowFuncs[n.Func.Name] {
+		for _, arg := range n.Args {
+			if m, ok := arg.(*promParser.MatrixSelector); ok {
+				windows = append(windows, rangeWindow{expr: node.Expr, fn: n.Func.Name, window: m.Range})
+			}
+		}
+	}
+	for _, child := range node.Children {
+		windows = append(windows, rangeWindows(child)...)
+	}
+	return windows
+}
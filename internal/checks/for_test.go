@@ -0,0 +1,191 @@
+package checks_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/rs/zerolog"
+)
+
+func TestForCheck(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1m/api/v1/status/config":
+			w.WriteHeader(200)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 2m\n  evaluation_interval: 1m\n"}}`))
+		case "/error/api/v1/status/config":
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("fake error\n"))
+		default:
+			w.WriteHeader(400)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled path"}`))
+		}
+	}))
+	defer srv.Close()
+
+	testCases := []checkTest{
+		{
+			description: "ignores recording rules",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Warning),
+		},
+		{
+			description: "ignores alerts without for",
+			content:     "- alert: foo\n  expr: foo > 1\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+		},
+		{
+			description: "ignores for: 0",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 0s\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+		},
+		{
+			description: "invalid for",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 5 minutes\n",
+			checker:     checks.NewForValueCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 5 minutes",
+					Lines:    []int{3},
+					Reporter: "alerts/for",
+					Text:     `invalid for value "5 minutes": not a valid duration string: "5 minutes"`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "invalid for is only reported once",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 1.5m\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+		},
+		{
+			description: "invalid for is only reported by the value check",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 1.5m\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Warning),
+		},
+		{
+			description: "value check ignores valid for",
+			content:     "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: rate(errors_total[5m]) > 0\n    for: 30s\n",
+			checker:     checks.NewForValueCheck(),
+		},
+		{
+			description: "valid for without group",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 1d\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Warning),
+		},
+		{
+			description: "for shorter than group interval",
+			content:     "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: foo > 1\n    for: 30s\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 30s",
+					Lines:    []int{7},
+					Reporter: "alerts/for",
+					Text:     "for: 30s is shorter than rule group interval of 2m, alerts will always be pending for at least one evaluation interval",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "for not a multiple of group interval",
+			content:     "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: foo > 1\n    for: 5m\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 5m",
+					Lines:    []int{7},
+					Reporter: "alerts/for",
+					Text:     "for: 5m is not a multiple of rule group interval of 2m, alerts will fire after being pending for 6m",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "group interval takes precedence over evaluation_interval",
+			content:     "groups:\n- name: foo\n  interval: 30s\n  rules:\n  - alert: foo\n    expr: foo > 1\n    for: 150s\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+		},
+		{
+			description: "for not a multiple of evaluation_interval",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 150s\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 150s",
+					Lines:    []int{3},
+					Reporter: "alerts/for",
+					Text:     "for: 150s is not a multiple of prom evaluation_interval of 1m, alerts will fire after being pending for 3m",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "for shorter than evaluation_interval and scrape_interval",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 30s\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 30s",
+					Lines:    []int{3},
+					Reporter: "alerts/for",
+					Text:     "for: 30s is shorter than prom evaluation_interval of 1m, alerts will always be pending for at least one evaluation interval",
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "for: 30s",
+					Lines:    []int{3},
+					Reporter: "alerts/for",
+					Text:     "for: 30s is shorter than prom scrape_interval of 2m, alert might fire and resolve on every scrape",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "for shorter than rate() window",
+			content:     "- alert: foo\n  expr: rate(errors_total[10m]) > 0 and increase(requests_total[1m]) > 0 and rate(errors_total[10m]) > 1\n  for: 5m\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Bug),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 5m",
+					Lines:    []int{3},
+					Reporter: "alerts/for",
+					Text:     "for: 5m is shorter than rate() window of 10m used in rate(errors_total[10m]), alert might fire and resolve multiple times for a single spike",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "for not shorter than rate() window",
+			content:     "- alert: foo\n  expr: irate(errors_total[5m]) > 0\n  for: 5m\n",
+			checker:     checks.NewForCheck("", "", time.Second, checks.Warning),
+		},
+		{
+			description: "rate() window is only compared by the check without uri",
+			content:     "- alert: foo\n  expr: rate(errors_total[10m]) > 0\n  for: 5m\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/1m/", time.Second, checks.Warning),
+		},
+		{
+			description: "config query error",
+			content:     "- alert: foo\n  expr: foo > 1\n  for: 5m\n",
+			checker:     checks.NewForCheck("prom", srv.URL+"/error/", time.Second, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "for: 5m",
+					Lines:    []int{3},
+					Reporter: "alerts/for",
+					Text:     "failed to query prom prometheus config: failed to query Prometheus config: server_error: server error: 500",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,252 @@
+package checks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatCheckName = "rule/format"
+)
+
+var (
+	// yaml decoding errors: "line 5: field foo not found in type rulefmt.RuleGroup"
+	yamlLineRe = regexp.MustCompile(`^line (\d+): (.+)$`)
+	// rulefmt validation errors: "5:3: groupname: \"foo\" is repeated in the same file"
+	posRe = regexp.MustCompile(`^(\d+):\d+: (.+)$`)
+)
+
+func NewFormatCheck(severity Severity) FormatCheck {
+	return FormatCheck{severity: severity}
+}
+
+// FormatCheck validates the whole file using the same code Prometheus uses
+// when loading rule files
+type FormatCheck struct {
+	severity Severity
+}
+
+func (c FormatCheck) String() string {
+	return FormatCheckName
+}
+
+func (c FormatCheck) CheckFile(content []byte, groups []parser.RuleGroup) (problems []Problem) {
+	for _, doc := range ruleDocuments(content) {
+		_, errs := rulefmt.Parse(doc.content)
+		for _, err := range errs {
+			for _, pe := range c.decodeError(doc.root, err) {
+				problems = append(problems, Problem{
+					Lines:    []int{pe.line},
+					Reporter: FormatCheckName,
+					Text:     fmt.Sprintf("Prometheus will fail to load this file: %s", pe.text),
+					Severity: c.severity,
+				})
+			}
+		}
+	}
+	return problems
+}
+
+type ruleDocument struct {
+	content []byte
+	root    *yaml.Node
+}
+
+// ruleDocuments splits content into YAML documents that can be passed to
+// rulefmt, which only decodes the first document, for PrometheusRule objects
+// only the spec is used and other Kubernetes objects are skipped, all lines
+// outside of each document are replaced with empty lines so line numbers in
+// rulefmt errors are still correct
+func ruleDocuments(content []byte) (docs []ruleDocument) {
+	type docRange struct {
+		root       *yaml.Node
+		start, end int
+	}
+	ranges := []docRange{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		// this can only fail on invalid YAML, which is reported by the parser
+		if err := dec.Decode(&node); err != nil {
+			break
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		root := node.Content[0]
+		if len(ranges) > 0 && ranges[len(ranges)-1].end == 0 {
+			ranges[len(ranges)-1].end = root.Line - 1
+		}
+		ranges = append(ranges, docRange{root: root, start: root.Line})
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	for _, r := range ranges {
+		if r.end == 0 {
+			r.end = len(lines)
+		}
+		root := r.root
+		if root.Kind == yaml.MappingNode && parser.MappingValue(root, "apiVersion") != nil && parser.MappingValue(root, "kind") != nil {
+			if !parser.IsPrometheusRule(root) {
+				continue
+			}
+			spec, next := specRange(root)
+			if spec == nil {
+				continue
+			}
+			root = spec
+			r.start = spec.Line
+			if next > 0 {
+				r.end = next - 1
+			}
+		}
+
+		doc := make([][]byte, len(lines))
+		for i, line := range lines {
+			if i+1 >= r.start && i+1 <= r.end {
+				doc[i] = line
+			}
+		}
+		docs = append(docs, ruleDocument{content: bytes.Join(doc, []byte("\n")), root: root})
+	}
+	return docs
+}
+
+// specRange returns the spec of a Kubernetes object and the line where the
+// next top level key starts, or 0 if spec is the last key
+func specRange(root *yaml.Node) (spec *yaml.Node, next int) {
+	for i := 0; i < len(root.Content)-1; i += 2 {
+		if spec != nil {
+			return spec, root.Content[i].Line
+		}
+		if root.Content[i].Value == "spec" && root.Content[i+1].Kind == yaml.MappingNode {
+			spec = root.Content[i+1]
+		}
+	}
+	return spec, 0
+}
+
+type positionedError struct {
+	line int
+	text string
+}
+
+func (c FormatCheck) decodeError(root *yaml.Node, err error) (errs []positionedError) {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		for _, e := range typeErr.Errors {
+			// rulefmt decodes the file twice, second time into an internal
+			// type, which can only fail if the first decoding failed too
+			if strings.Contains(e, "rulefmt.ruleGroups") {
+				continue
+			}
+			if parts := yamlLineRe.FindStringSubmatch(e); len(parts) == 3 {
+				line, _ := strconv.Atoi(parts[1])
+				errs = append(errs, positionedError{line: line, text: parts[2]})
+			} else {
+				errs = append(errs, positionedError{line: root.Line, text: e})
+			}
+		}
+		return errs
+	}
+
+	var ruleErr *rulefmt.Error
+	if errors.As(err, &ruleErr) {
+		// error text is prefixed with rule details, we only want to keep the
+		// error message itself
+		msg := ruleErr.Error()
+		prefix := fmt.Sprintf("group %q, rule %d, %q: ", ruleErr.Group, ruleErr.Rule, ruleErr.RuleName)
+		text := msg
+		if i := strings.Index(msg, prefix); i >= 0 {
+			text = msg[i+len(prefix):]
+		}
+		// line numbers embedded in the error text are not always correct
+		// so we use the line where the rule starts
+		return append(errs, positionedError{line: findRuleLine(root, ruleErr.Group, ruleErr.Rule), text: text})
+	}
+
+	if parts := posRe.FindStringSubmatch(err.Error()); len(parts) == 3 {
+		line, _ := strconv.Atoi(parts[1])
+		return append(errs, positionedError{line: line, text: parts[2]})
+	}
+	// errors returned by custom unmarshal functions, like the one used for
+	// durations, don't have any position so we need to find it ourselves
+	return append(errs, positionedError{line: findErrorLine(root, err), text: err.Error()})
+}
+
+// findErrorLine returns the line of the key that fails to decode with given
+// error, each key is decoded on its own into the same type rulefmt uses for
+// it, if no key fails that way then the line where the document starts is
+// returned
+func findErrorLine(root *yaml.Node, err error) int {
+	groups := parser.MappingValue(root, "groups")
+	if groups == nil || groups.Kind != yaml.SequenceNode {
+		return root.Line
+	}
+	for _, g := range groups.Content {
+		if g.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i < len(g.Content)-1; i += 2 {
+			key, value := g.Content[i], g.Content[i+1]
+			if key.Value != "rules" {
+				if failsWith(key, value, &rulefmt.RuleGroup{}, err) {
+					return key.Line
+				}
+				continue
+			}
+			if value.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, r := range value.Content {
+				if r.Kind != yaml.MappingNode {
+					continue
+				}
+				for j := 0; j < len(r.Content)-1; j += 2 {
+					if failsWith(r.Content[j], r.Content[j+1], &rulefmt.RuleNode{}, err) {
+						return r.Content[j].Line
+					}
+				}
+			}
+		}
+	}
+	return root.Line
+}
+
+func failsWith(key, value *yaml.Node, out interface{}, err error) bool {
+	node := yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{key, value}}
+	if e := node.Decode(out); e != nil {
+		return e.Error() == err.Error()
+	}
+	return false
+}
+
+// findRuleLine returns the line where given rule starts, root is the mapping
+// node with groups, rule index starts at 1, same as in rulefmt errors
+func findRuleLine(root *yaml.Node, group string, rule int) int {
+	groups := parser.MappingValue(root, "groups")
+	if groups == nil || groups.Kind != yaml.SequenceNode {
+		return root.Line
+	}
+	for _, g := range groups.Content {
+		if name := parser.MappingValue(g, "name"); name == nil || name.Value != group {
+			continue
+		}
+		rules := parser.MappingValue(g, "rules")
+		if rules == nil || rules.Kind != yaml.SequenceNode || rule < 1 || rule > len(rules.Content) {
+			return g.Line
+		}
+		return rules.Content[rule-1].Line
+	}
+	return root.Line
+}
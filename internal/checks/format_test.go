@@ -0,0 +1,242 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestFormatCheck(t *testing.T) {
+	testCases := []fileCheckTest{
+		{
+			description: "valid file",
+			content: `groups:
+- name: foo
+  interval: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+  - alert: foo
+    expr: up == 0
+    for: 5m
+    labels:
+      severity: critical
+    annotations:
+      summary: '{{ $labels.job }} is down'
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+		},
+		{
+			description: "rules outside of groups",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{1},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: cannot unmarshal !!seq into rulefmt.RuleGroups",
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "unknown keys",
+			content: `groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(foo)
+    bogus: 1
+  extra: true
+`,
+			checker: checks.NewFormatCheck(checks.Bug),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{6},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: field bogus not found in type rulefmt.RuleNode",
+					Severity: checks.Bug,
+				},
+				{
+					Lines:    []int{7},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: field extra not found in type rulefmt.RuleGroup",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "invalid rules",
+			content: `groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(foo
+  - record: foo
+    expr: sum(foo)
+    labels:
+      __name__: bar
+- name: foo
+  rules: []
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{4},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: could not parse expression: 1:8: parse error: unclosed left parenthesis",
+					Severity: checks.Fatal,
+				},
+				{
+					Lines:    []int{6},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: invalid label name: __name__",
+					Severity: checks.Fatal,
+				},
+				{
+					Lines:    []int{10},
+					Reporter: "rule/format",
+					Text:     `Prometheus will fail to load this file: groupname: "foo" is repeated in the same file`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "multiple documents",
+			content: `groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(foo)
+---
+groups:
+- name: bar
+  rules:
+  - record: bar
+    expr: sum(bar)
+    for: 5m
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{10},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: invalid field 'for' in recording rule",
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "PrometheusRule objects",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+data:
+  foo: bar
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups:
+  - name: foo
+    rules:
+    - record: foo
+      expr: sum(foo)
+      bogus: true
+    - record: bar
+      expr: sum(bar)
+      for: 5m
+status:
+  foo: bar
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{18},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: field bogus not found in type rulefmt.RuleNode",
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "invalid rule in PrometheusRule object",
+			content: `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups:
+  - name: foo
+    rules:
+    - record: foo
+      expr: sum(foo)
+    - record: bar
+      expr: sum(bar)
+      for: 5m
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{11},
+					Reporter: "rule/format",
+					Text:     "Prometheus will fail to load this file: invalid field 'for' in recording rule",
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "invalid for",
+			content: `groups:
+- name: foo
+  rules:
+  - alert: foo
+    expr: up == 0
+    for: 5 minutes
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{6},
+					Reporter: "rule/format",
+					Text:     `Prometheus will fail to load this file: not a valid duration string: "5 minutes"`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "invalid group interval in PrometheusRule object",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups:
+  - name: foo
+    interval: 1 minute
+    rules:
+    - alert: foo
+      expr: up == 0
+      for: 5m
+`,
+			checker: checks.NewFormatCheck(checks.Fatal),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{13},
+					Reporter: "rule/format",
+					Text:     `Prometheus will fail to load this file: not a valid duration string: "1 minute"`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+	}
+	runFileTests(t, testCases)
+}
@@ -0,0 +1,143 @@
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	GroupCheckName = "rule/group"
+)
+
+func NewGroupCheck() GroupCheck {
+	return GroupCheck{}
+}
+
+// GroupCheck validates rule group fields the same way Prometheus does when
+// loading rule files
+type GroupCheck struct{}
+
+func (c GroupCheck) String() string {
+	return GroupCheckName
+}
+
+// groupName is used to find duplicated group names, those need to be unique
+// within a single YAML document
+type groupName struct {
+	document int
+	name     string
+}
+
+func (c GroupCheck) CheckFile(content []byte, groups []parser.RuleGroup) (problems []Problem) {
+	names := map[groupName]int{}
+	for _, group := range groups {
+		problems = append(problems, c.checkName(group, names)...)
+		problems = append(problems, c.checkInterval(group)...)
+		problems = append(problems, c.checkLimit(group)...)
+		problems = append(problems, c.checkPartialResponseStrategy(group)...)
+		problems = append(problems, c.checkUnknownKeys(group)...)
+	}
+	return problems
+}
+
+func (c GroupCheck) checkName(group parser.RuleGroup, names map[groupName]int) (problems []Problem) {
+	if group.Name == nil || group.Name.Value.Value == "" {
+		problems = append(problems, Problem{
+			Lines:    group.Lines(),
+			Reporter: GroupCheckName,
+			Text:     "group name must not be empty",
+			Severity: Fatal,
+		})
+		return problems
+	}
+
+	name := group.Name.Value.Value
+	key := groupName{document: group.Document, name: name}
+	if line, ok := names[key]; ok {
+		problems = append(problems, Problem{
+			Fragment: name,
+			Lines:    group.Name.Lines(),
+			Reporter: GroupCheckName,
+			Text:     fmt.Sprintf("duplicated group name %q, it's already used by a group defined on line %d", name, line),
+			Severity: Fatal,
+		})
+		return problems
+	}
+	names[key] = group.Name.Key.Position.FistLine()
+	return problems
+}
+
+func (c GroupCheck) checkInterval(group parser.RuleGroup) (problems []Problem) {
+	if group.Interval == nil {
+		return nil
+	}
+	if _, err := model.ParseDuration(group.Interval.Value.Value); err != nil {
+		problems = append(problems, Problem{
+			Fragment: group.Interval.Value.Value,
+			Lines:    group.Interval.Lines(),
+			Reporter: GroupCheckName,
+			Text:     fmt.Sprintf("invalid interval value: %s", err),
+			Severity: Fatal,
+		})
+	}
+	return problems
+}
+
+func (c GroupCheck) checkLimit(group parser.RuleGroup) (problems []Problem) {
+	if group.Limit == nil {
+		return nil
+	}
+	if _, err := strconv.Atoi(group.Limit.Value.Value); err != nil {
+		problems = append(problems, Problem{
+			Fragment: group.Limit.Value.Value,
+			Lines:    group.Limit.Lines(),
+			Reporter: GroupCheckName,
+			Text:     fmt.Sprintf("invalid limit value %q, it must be an integer", group.Limit.Value.Value),
+			Severity: Fatal,
+		})
+	}
+	return problems
+}
+
+func (c GroupCheck) checkPartialResponseStrategy(group parser.RuleGroup) (problems []Problem) {
+	if group.PartialResponseStrategy == nil {
+		return nil
+	}
+	switch strings.ToLower(group.PartialResponseStrategy.Value.Value) {
+	case "warn", "abort":
+	default:
+		problems = append(problems, Problem{
+			Fragment: group.PartialResponseStrategy.Value.Value,
+			Lines:    group.PartialResponseStrategy.Lines(),
+			Reporter: GroupCheckName,
+			Text:     fmt.Sprintf("invalid partial_response_strategy value %q, it must be one of: warn, abort", group.PartialResponseStrategy.Value.Value),
+			Severity: Fatal,
+		})
+	}
+	return problems
+}
+
+func (c GroupCheck) checkUnknownKeys(group parser.RuleGroup) (problems []Problem) {
+	if len(group.UnknownKeys) == 0 {
+		return nil
+	}
+
+	var keys []string
+	var lines []int
+	for _, key := range group.UnknownKeys {
+		keys = append(keys, key.Value)
+		lines = append(lines, key.Position.Lines...)
+	}
+	problems = append(problems, Problem{
+		Lines:    lines,
+		Reporter: GroupCheckName,
+		Text:     fmt.Sprintf("invalid group key(s) found: %s", strings.Join(keys, ", ")),
+		Severity: Fatal,
+	})
+	return problems
+}
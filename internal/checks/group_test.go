@@ -0,0 +1,171 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestGroupCheck(t *testing.T) {
+	testCases := []fileCheckTest{
+		{
+			description: "rules without groups",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     checks.NewGroupCheck(),
+		},
+		{
+			description: "valid groups",
+			content: `groups:
+- name: foo
+  interval: 1m
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo)
+- name: bar
+  partial_response_strategy: abort
+  rules: []
+`,
+			checker: checks.NewGroupCheck(),
+		},
+		{
+			description: "missing name",
+			content: `groups:
+- interval: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+- name: ""
+  rules: []
+- rules: []
+`,
+			checker: checks.NewGroupCheck(),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{2},
+					Reporter: "rule/group",
+					Text:     "group name must not be empty",
+					Severity: checks.Fatal,
+				},
+				{
+					Lines:    []int{6},
+					Reporter: "rule/group",
+					Text:     "group name must not be empty",
+					Severity: checks.Fatal,
+				},
+				{
+					Lines:    []int{8},
+					Reporter: "rule/group",
+					Text:     "group name must not be empty",
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "duplicated name",
+			content: `groups:
+- name: foo
+  rules: []
+- name: bar
+  rules: []
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker: checks.NewGroupCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{6},
+					Reporter: "rule/group",
+					Text:     `duplicated group name "foo", it's already used by a group defined on line 2`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "same name in different documents",
+			content: `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+spec:
+  groups:
+  - name: foo
+    rules: []
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+spec:
+  groups:
+  - name: foo
+    rules: []
+  - name: foo
+    rules: []
+`,
+			checker: checks.NewGroupCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{14},
+					Reporter: "rule/group",
+					Text:     `duplicated group name "foo", it's already used by a group defined on line 12`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "invalid values",
+			content: `groups:
+- name: foo
+  interval: 1 minute
+  limit: 1.5
+  partial_response_strategy: ignore
+  rules: []
+`,
+			checker: checks.NewGroupCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "1 minute",
+					Lines:    []int{3},
+					Reporter: "rule/group",
+					Text:     `invalid interval value: not a valid duration string: "1 minute"`,
+					Severity: checks.Fatal,
+				},
+				{
+					Fragment: "1.5",
+					Lines:    []int{4},
+					Reporter: "rule/group",
+					Text:     `invalid limit value "1.5", it must be an integer`,
+					Severity: checks.Fatal,
+				},
+				{
+					Fragment: "ignore",
+					Lines:    []int{5},
+					Reporter: "rule/group",
+					Text:     `invalid partial_response_strategy value "ignore", it must be one of: warn, abort`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "unknown keys",
+			content: `groups:
+- name: foo
+  labels:
+    foo: bar
+  eval_interval: 1m
+  rules: []
+`,
+			checker: checks.NewGroupCheck(),
+			problems: []checks.Problem{
+				{
+					Lines:    []int{3, 5},
+					Reporter: "rule/group",
+					Text:     "invalid group key(s) found: labels, eval_interval",
+					Severity: checks.Fatal,
+				},
+			},
+		},
+	}
+	runFileTests(t, testCases)
+}
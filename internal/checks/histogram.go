@@ -0,0 +1,163 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	HistogramCheckName = "promql/histogram"
+)
+
+// NewHistogramCheck creates a check reporting histogram_quantile() calls that
+// will always return wrong results, it doesn't need any configuration so it's
+// always enabled
+func NewHistogramCheck() HistogramCheck {
+	return HistogramCheck{}
+}
+
+// NewHistogramSuffixCheck creates a check reporting metrics without _bucket
+// suffix passed to histogram_quantile(), those are only guessed from metric
+// names so this check needs to be enabled explicitly
+func NewHistogramSuffixCheck(severity Severity) HistogramCheck {
+	return HistogramCheck{suffix: true, severity: severity}
+}
+
+// HistogramCheck finds common mistakes when using histogram_quantile()
+type HistogramCheck struct {
+	suffix   bool
+	severity Severity
+}
+
+func (c HistogramCheck) String() string {
+	return HistogramCheckName
+}
+
+func (c HistogramCheck) Check(rule parser.Rule) (problems []Problem) {
+	expr := rule.Expr()
+
+	if expr.SyntaxError != nil {
+		return
+	}
+
+	for _, problem := range c.checkNode(expr.Query) {
+		problems = append(problems, Problem{
+			Fragment: problem.expr,
+			Lines:    expr.Lines(),
+			Reporter: HistogramCheckName,
+			Text:     problem.text,
+			Severity: problem.severity,
+		})
+	}
+
+	return
+}
+
+func (c HistogramCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem) {
+	if n, ok := node.Node.(*promParser.Call); ok && n.Func.Name == "histogram_quantile" && len(node.Children) == 2 {
+		if c.suffix {
+			problems = append(problems, c.checkSelectors(node.Children[1], false)...)
+		} else {
+			problems = append(problems, c.checkQuantile(node.Children[0])...)
+			problems = append(problems, c.checkBuckets(node.Children[1])...)
+		}
+	}
+
+	for _, child := range node.Children {
+		problems = append(problems, c.checkNode(child)...)
+	}
+
+	return
+}
+
+func (c HistogramCheck) checkQuantile(node *parser.PromQLNode) (problems []exprProblem) {
+	var val promParser.Node = node.Node
+	for {
+		p, ok := val.(*promParser.ParenExpr)
+		if !ok {
+			break
+		}
+		val = p.Expr
+	}
+	n, ok := val.(*promParser.NumberLiteral)
+	if !ok {
+		return nil
+	}
+	var result string
+	switch {
+	case n.Val < 0:
+		result = "-Inf"
+	case n.Val > 1:
+		result = "+Inf"
+	default:
+		return nil
+	}
+	problems = append(problems, exprProblem{
+		expr:     node.Expr,
+		text:     fmt.Sprintf("histogram_quantile() quantile must be between 0 and 1, using %s will always return %s", node.Expr, result),
+		severity: Bug,
+	})
+	return problems
+}
+
+func (c HistogramCheck) checkBuckets(node *parser.PromQLNode) (problems []exprProblem) {
+	if ok, removedBy := queryLabels(node).canHave("le"); !ok {
+		problems = append(problems, exprProblem{
+			expr:     removedBy,
+			text:     "histogram_quantile() requires the le label but this query removes it, aggregations must preserve it with by(le) or not remove it with without(le)",
+			severity: Bug,
+		})
+	}
+
+	problems = append(problems, c.checkSelectors(node, false)...)
+
+	return problems
+}
+
+// checkSelectors returns problems for all raw metrics used as
+// histogram_quantile() input, if suffix is set then it only reports metrics
+// that are not buckets, otherwise it only reports buckets not passed to any
+// function that would calculate per second rate
+func (c HistogramCheck) checkSelectors(node *parser.PromQLNode, inCall bool) (problems []exprProblem) {
+	switch n := node.Node.(type) {
+	case *promParser.Call:
+		// nested histogram_quantile calls are checked on their own
+		if n.Func.Name == "histogram_quantile" {
+			return nil
+		}
+		inCall = true
+	case *promParser.VectorSelector:
+		// recording rules don't always keep the _bucket suffix
+		if n.Name == "" || strings.Contains(n.Name, ":") {
+			return nil
+		}
+		if !strings.HasSuffix(n.Name, "_bucket") {
+			if !c.suffix {
+				return nil
+			}
+			return append(problems, exprProblem{
+				expr:     node.Expr,
+				text:     fmt.Sprintf("histogram_quantile() should be used with histogram buckets but %s doesn't have _bucket suffix", n.Name),
+				severity: c.severity,
+			})
+		}
+		if !c.suffix && !inCall {
+			return append(problems, exprProblem{
+				expr:     node.Expr,
+				text:     fmt.Sprintf("histogram_quantile() should be used with rate() of %s, raw bucket counters will calculate quantiles of all observations since the counter was created", n.Name),
+				severity: Bug,
+			})
+		}
+		return nil
+	}
+
+	for _, child := range node.Children {
+		problems = append(problems, c.checkSelectors(child, inCall)...)
+	}
+
+	return problems
+}
@@ -0,0 +1,141 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestHistogramCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     checks.NewHistogramCheck(),
+		},
+		{
+			description: "valid query",
+			content:     "- record: foo\n  expr: histogram_quantile(0.99, sum(rate(foo_bucket[5m])) by (le, job))\n",
+			checker:     checks.NewHistogramCheck(),
+		},
+		{
+			description: "valid query using without",
+			content:     "- record: foo\n  expr: histogram_quantile((0.5), sum without(instance) (rate(foo_bucket[5m])))\n",
+			checker:     checks.NewHistogramCheck(),
+		},
+		{
+			description: "valid query using recording rule",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, job_le:foo:rate5m)\n",
+			checker:     checks.NewHistogramCheck(),
+		},
+		{
+			description: "quantile > 1",
+			content:     "- record: foo\n  expr: histogram_quantile(99, sum(rate(foo_bucket[5m])) by (le))\n",
+			checker:     checks.NewHistogramCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "99",
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() quantile must be between 0 and 1, using 99 will always return +Inf",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "quantile < 0",
+			content:     "- record: foo\n  expr: histogram_quantile(-0.5, sum(rate(foo_bucket[5m])) by (le))\n",
+			checker:     checks.NewHistogramCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "-0.5",
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() quantile must be between 0 and 1, using -0.5 will always return -Inf",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "aggregation removes le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by (job))\n",
+			checker:     checks.NewHistogramCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum by(job) (rate(foo_bucket[5m]))",
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() requires the le label but this query removes it, aggregations must preserve it with by(le) or not remove it with without(le)",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "without(le)",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum without(le) (rate(foo_bucket[5m])))\n",
+			checker:     checks.NewHistogramCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum without(le) (rate(foo_bucket[5m]))",
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() requires the le label but this query removes it, aggregations must preserve it with by(le) or not remove it with without(le)",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "not a bucket",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, rate(foo_count[5m]))\n",
+			checker:     checks.NewHistogramSuffixCheck(checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo_count",
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() should be used with histogram buckets but foo_count doesn't have _bucket suffix",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "not a bucket is only reported when suffix check is enabled",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, rate(foo_count[5m]))\n",
+			checker:     checks.NewHistogramCheck(),
+		},
+		{
+			description: "suffix check only reports suffix",
+			content:     "- alert: foo\n  expr: histogram_quantile(2, sum(foo{job=\"bar\"}) by (job)) > 1\n",
+			checker:     checks.NewHistogramSuffixCheck(checks.Information),
+			problems: []checks.Problem{
+				{
+					Fragment: `foo{job="bar"}`,
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() should be used with histogram buckets but foo doesn't have _bucket suffix",
+					Severity: checks.Information,
+				},
+			},
+		},
+		{
+			description: "suffix check ignores missing rate",
+			content:     "- alert: foo\n  expr: histogram_quantile(0.9, sum(foo_bucket{job=\"bar\"}) by (le)) > 1\n",
+			checker:     checks.NewHistogramSuffixCheck(checks.Warning),
+		},
+		{
+			description: "missing rate",
+			content:     "- alert: foo\n  expr: histogram_quantile(0.9, sum(foo_bucket{job=\"bar\"}) by (le)) > 1\n",
+			checker:     checks.NewHistogramCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: `foo_bucket{job="bar"}`,
+					Lines:    []int{2},
+					Reporter: "promql/histogram",
+					Text:     "histogram_quantile() should be used with rate() of foo_bucket, raw bucket counters will calculate quantiles of all observations since the counter was created",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
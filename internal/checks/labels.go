@@ -0,0 +1,246 @@
+package checks
+
+import (
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+// outputLabels describes which labels can be present on results of a query
+type outputLabels struct {
+	// fixed is true if we know the exact list of labels that can be present,
+	// those are stored in include and fixedBy is the part of the query
+	// that removed all other labels
+	fixed   bool
+	fixedBy string
+	include map[string]struct{}
+	// removed maps labels that are known to be removed to the part of the
+	// query that removes them
+	removed map[string]string
+}
+
+func anyLabels() outputLabels {
+	return outputLabels{
+		include: map[string]struct{}{},
+		removed: map[string]string{},
+	}
+}
+
+func noLabels(expr string) outputLabels {
+	ol := anyLabels()
+	ol.fixed = true
+	ol.fixedBy = expr
+	return ol
+}
+
+// canHave returns false if given label is never present on query results,
+// in which case it will also return the part of the query that removes it
+func (ol outputLabels) canHave(name string) (bool, string) {
+	if expr, ok := ol.removed[name]; ok {
+		return false, expr
+	}
+	if _, ok := ol.include[name]; ol.fixed && !ok {
+		return false, ol.fixedBy
+	}
+	return true, ""
+}
+
+func (ol outputLabels) keep(names []string, expr string) outputLabels {
+	keep := map[string]struct{}{}
+	for _, name := range names {
+		keep[name] = struct{}{}
+	}
+
+	if ol.fixed {
+		for name := range ol.include {
+			if _, ok := keep[name]; !ok {
+				delete(ol.include, name)
+				ol.removed[name] = expr
+			}
+		}
+		return ol
+	}
+
+	ol.fixed = true
+	ol.fixedBy = expr
+	for name := range keep {
+		if _, ok := ol.removed[name]; !ok {
+			ol.include[name] = struct{}{}
+		}
+	}
+	return ol
+}
+
+func (ol outputLabels) drop(names []string, expr string) outputLabels {
+	for _, name := range names {
+		delete(ol.include, name)
+		if _, ok := ol.removed[name]; !ok {
+			ol.removed[name] = expr
+		}
+	}
+	return ol
+}
+
+func (ol outputLabels) add(names ...string) outputLabels {
+	for _, name := range names {
+		delete(ol.removed, name)
+		if ol.fixed {
+			ol.include[name] = struct{}{}
+		}
+	}
+	return ol
+}
+
+// union returns labels that can be present on results of either query
+func (ol outputLabels) union(other outputLabels) outputLabels {
+	result := anyLabels()
+	if ol.fixed && other.fixed {
+		result.fixed = true
+		result.fixedBy = ol.fixedBy
+		for name := range ol.include {
+			result.include[name] = struct{}{}
+		}
+		for name := range other.include {
+			result.include[name] = struct{}{}
+		}
+	}
+	for name, expr := range ol.removed {
+		if ok, _ := other.canHave(name); !ok {
+			result.removed[name] = expr
+		}
+	}
+	for name, expr := range other.removed {
+		if ok, _ := ol.canHave(name); !ok {
+			if _, found := result.removed[name]; !found {
+				result.removed[name] = expr
+			}
+		}
+	}
+	return result
+}
+
+// queryLabels walks the query tree and returns labels that can be present
+// on query results
+func queryLabels(node *parser.PromQLNode) outputLabels {
+	switch n := node.Node.(type) {
+	case *promParser.VectorSelector:
+		return anyLabels()
+	case *promParser.NumberLiteral, *promParser.StringLiteral:
+		return noLabels(node.Expr)
+	case *promParser.AggregateExpr:
+		return aggregationLabels(node, n)
+	case *promParser.BinaryExpr:
+		return binaryExprLabels(node, n)
+	case *promParser.Call:
+		return callLabels(node, n)
+	case *promParser.MatrixSelector, *promParser.SubqueryExpr, *promParser.ParenExpr,
+		*promParser.UnaryExpr, *promParser.StepInvariantExpr:
+		return queryLabels(node.Children[0])
+	}
+	return anyLabels()
+}
+
+func aggregationLabels(node *parser.PromQLNode, n *promParser.AggregateExpr) outputLabels {
+	ol := queryLabels(node.Children[0])
+
+	switch n.Op {
+	case promParser.TOPK, promParser.BOTTOMK:
+		// topk & bottomk return original series
+		return ol
+	}
+
+	if n.Without {
+		ol = ol.drop(n.Grouping, node.Expr)
+	} else {
+		ol = ol.keep(n.Grouping, node.Expr)
+	}
+
+	if s, ok := n.Param.(*promParser.StringLiteral); ok && n.Op == promParser.COUNT_VALUES {
+		ol = ol.add(s.Val)
+	}
+
+	return ol
+}
+
+func binaryExprLabels(node *parser.PromQLNode, n *promParser.BinaryExpr) outputLabels {
+	lhs := n.LHS.Type() == promParser.ValueTypeVector
+	rhs := n.RHS.Type() == promParser.ValueTypeVector
+
+	switch {
+	case lhs && !rhs:
+		return queryLabels(node.Children[0])
+	case !lhs && rhs:
+		return queryLabels(node.Children[1])
+	case !lhs && !rhs:
+		return noLabels(node.Expr)
+	}
+
+	if n.VectorMatching == nil {
+		return queryLabels(node.Children[0])
+	}
+
+	switch n.VectorMatching.Card {
+	case promParser.CardManyToMany:
+		if n.Op == promParser.LOR {
+			return queryLabels(node.Children[0]).union(queryLabels(node.Children[1]))
+		}
+		// and & unless return series from the left hand side
+		return queryLabels(node.Children[0])
+	case promParser.CardManyToOne:
+		return queryLabels(node.Children[0]).add(n.VectorMatching.Include...)
+	case promParser.CardOneToMany:
+		return queryLabels(node.Children[1]).add(n.VectorMatching.Include...)
+	}
+
+	ol := queryLabels(node.Children[0])
+	if n.VectorMatching.On {
+		return ol.keep(n.VectorMatching.MatchingLabels, node.Expr)
+	}
+	return ol.drop(n.VectorMatching.MatchingLabels, node.Expr)
+}
+
+func callLabels(node *parser.PromQLNode, n *promParser.Call) outputLabels {
+	if n.Func.ReturnType != promParser.ValueTypeVector {
+		return noLabels(node.Expr)
+	}
+
+	switch n.Func.Name {
+	case "absent", "absent_over_time":
+		// absent() will only return labels from equality matchers
+		ol := noLabels(node.Expr)
+		var matchers []*labels.Matcher
+		switch s := n.Args[0].(type) {
+		case *promParser.VectorSelector:
+			matchers = s.LabelMatchers
+		case *promParser.MatrixSelector:
+			if vs, ok := s.VectorSelector.(*promParser.VectorSelector); ok {
+				matchers = vs.LabelMatchers
+			}
+		}
+		for _, m := range matchers {
+			if m.Type == labels.MatchEqual && m.Name != labels.MetricName {
+				ol.include[m.Name] = struct{}{}
+			}
+		}
+		return ol
+	case "label_replace", "label_join":
+		ol := queryLabels(node.Children[0])
+		if s, ok := n.Args[1].(*promParser.StringLiteral); ok {
+			ol = ol.add(s.Val)
+		}
+		return ol
+	case "histogram_quantile":
+		return queryLabels(node.Children[1]).drop([]string{"le"}, node.Expr)
+	}
+
+	for i, arg := range n.Args {
+		switch arg.Type() {
+		case promParser.ValueTypeVector, promParser.ValueTypeMatrix:
+			return queryLabels(node.Children[i])
+		}
+	}
+
+	// vector(), time() and similar functions return series without labels
+	return noLabels(node.Expr)
+}
@@ -0,0 +1,275 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/common/model"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	NamingCheckName = "promql/naming"
+)
+
+var (
+	// operations describing range functions, like rate5m
+	rangeOperationRe = regexp.MustCompile(`^(rate|irate|increase)((?:[0-9]+[smhdwy])+)$`)
+
+	// aggregations that should be listed in operations, sum is usually omitted
+	namedAggregations = map[promParser.ItemType][]string{
+		promParser.AVG:    {"avg", "mean"},
+		promParser.MIN:    {"min"},
+		promParser.MAX:    {"max"},
+		promParser.COUNT:  {"count"},
+		promParser.STDDEV: {"stddev"},
+		promParser.STDVAR: {"stdvar"},
+	}
+)
+
+func NewNamingCheck(metric, operations, level bool, severity Severity) NamingCheck {
+	return NamingCheck{metric: metric, operations: operations, level: level, severity: severity}
+}
+
+// NamingCheck validates that recording rule names follow the
+// level:metric:operations naming convention, metric, operations and level
+// flags enable stricter validation of each part of the name
+type NamingCheck struct {
+	metric     bool
+	operations bool
+	level      bool
+	severity   Severity
+}
+
+func (c NamingCheck) String() string {
+	return NamingCheckName
+}
+
+func (c NamingCheck) Check(rule parser.Rule) (problems []Problem) {
+	if rule.RecordingRule == nil {
+		return nil
+	}
+
+	name := rule.RecordingRule.Record.Value.Value
+	parts := strings.Split(name, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		problems = append(problems, c.problem(rule, "recording rule name should follow level:metric:operations naming convention"))
+		return problems
+	}
+
+	expr := rule.RecordingRule.Expr
+	if expr.SyntaxError != nil {
+		return problems
+	}
+
+	if c.level {
+		problems = append(problems, c.checkLevel(rule, parts[0], expr.Query)...)
+	}
+	if c.metric {
+		problems = append(problems, c.checkMetric(rule, parts[1], expr.Query)...)
+	}
+	if c.operations {
+		problems = append(problems, c.checkOperations(rule, parts[2], expr.Query)...)
+	}
+
+	return problems
+}
+
+func (c NamingCheck) problem(rule parser.Rule, text string) Problem {
+	return Problem{
+		Fragment: rule.RecordingRule.Record.Value.Value,
+		Lines:    rule.RecordingRule.Record.Lines(),
+		Reporter: NamingCheckName,
+		Text:     text,
+		Severity: c.severity,
+	}
+}
+
+func (c NamingCheck) checkLevel(rule parser.Rule, level string, node *parser.PromQLNode) (problems []Problem) {
+	aggr := outermostAggregation(node)
+	if aggr == nil || aggr.Without || len(aggr.Grouping) == 0 || len(aggr.Grouping) > 6 {
+		return nil
+	}
+	switch aggr.Op {
+	case promParser.TOPK, promParser.BOTTOMK:
+		return nil
+	}
+
+	for _, perm := range permutations(aggr.Grouping) {
+		if strings.Join(perm, "_") == level {
+			return nil
+		}
+	}
+
+	labels := make([]string, len(aggr.Grouping))
+	copy(labels, aggr.Grouping)
+	sort.Strings(labels)
+	problems = append(problems, c.problem(rule, fmt.Sprintf(
+		"recording rule level %q doesn't match labels preserved by the query, it should be %q",
+		level, strings.Join(labels, "_"))))
+	return problems
+}
+
+func (c NamingCheck) checkMetric(rule parser.Rule, metric string, node *parser.PromQLNode) (problems []Problem) {
+	names := map[string]struct{}{}
+	for _, vs := range vectorSelectors(node) {
+		name := vs.Name
+		if parts := strings.Split(name, ":"); len(parts) == 3 {
+			name = parts[1]
+		}
+		if name == "" {
+			continue
+		}
+		names[name] = struct{}{}
+		names[strings.TrimSuffix(name, "_total")] = struct{}{}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// ratios are usually named foo_per_bar
+	var missing []string
+	for _, part := range strings.Split(metric, "_per_") {
+		if _, ok := names[part]; !ok {
+			missing = append(missing, part)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if _, ok := names[metric]; ok {
+		return nil
+	}
+
+	used := make([]string, 0, len(names))
+	for _, vs := range vectorSelectors(node) {
+		if vs.Name != "" {
+			used = appendUnique(used, vs.Name)
+		}
+	}
+	problems = append(problems, c.problem(rule, fmt.Sprintf(
+		"recording rule metric %q doesn't match any metric used in the query: %s",
+		metric, strings.Join(used, ", "))))
+	return problems
+}
+
+func (c NamingCheck) checkOperations(rule parser.Rule, operations string, node *parser.PromQLNode) (problems []Problem) {
+	tokens := map[string]struct{}{}
+	for _, token := range strings.Split(operations, "_") {
+		tokens[token] = struct{}{}
+	}
+
+	used := map[string]struct{}{}
+	for _, op := range queryOperations(node) {
+		// each operation is only reported once, even if it's used multiple
+		// times in the query
+		if _, ok := used[op[0]]; ok {
+			continue
+		}
+		used[op[0]] = struct{}{}
+		var found bool
+		for _, alias := range op {
+			if _, ok := tokens[alias]; ok {
+				found = true
+			}
+		}
+		if !found {
+			problems = append(problems, c.problem(rule, fmt.Sprintf(
+				"recording rule operations %q should include %s since it's used in the query",
+				operations, op[0])))
+		}
+	}
+
+	reported := map[string]struct{}{}
+	for _, token := range strings.Split(operations, "_") {
+		if !rangeOperationRe.MatchString(token) {
+			continue
+		}
+		if _, ok := reported[token]; ok {
+			continue
+		}
+		reported[token] = struct{}{}
+		if _, ok := used[token]; !ok {
+			problems = append(problems, c.problem(rule, fmt.Sprintf(
+				"recording rule operations %q include %s but it's not used in the query",
+				operations, token)))
+		}
+	}
+
+	return problems
+}
+
+// queryOperations returns the list of operations used in a query that should
+// be included in the recording rule name, each operation is a list of
+// accepted names
+func queryOperations(node *parser.PromQLNode) (ops [][]string) {
+	switch n := node.Node.(type) {
+	case *promParser.Call:
+		switch n.Func.Name {
+		case "rate", "irate", "increase":
+			for _, arg := range n.Args {
+				if ms, ok := arg.(*promParser.MatrixSelector); ok {
+					ops = append(ops, []string{n.Func.Name + model.Duration(ms.Range).String()})
+				}
+			}
+		}
+	case *promParser.AggregateExpr:
+		if names, ok := namedAggregations[n.Op]; ok {
+			ops = append(ops, names)
+		}
+	}
+	for _, child := range node.Children {
+		ops = append(ops, queryOperations(child)...)
+	}
+	return ops
+}
+
+func outermostAggregation(node *parser.PromQLNode) *promParser.AggregateExpr {
+	if n, ok := node.Node.(*promParser.AggregateExpr); ok {
+		return n
+	}
+	for _, child := range node.Children {
+		if aggr := outermostAggregation(child); aggr != nil {
+			return aggr
+		}
+	}
+	return nil
+}
+
+func vectorSelectors(node *parser.PromQLNode) (vss []*promParser.VectorSelector) {
+	if n, ok := node.Node.(*promParser.VectorSelector); ok {
+		vss = append(vss, n)
+	}
+	for _, child := range node.Children {
+		vss = append(vss, vectorSelectors(child)...)
+	}
+	return vss
+}
+
+func permutations(s []string) (perms [][]string) {
+	if len(s) <= 1 {
+		return [][]string{s}
+	}
+	for i := range s {
+		rest := make([]string, 0, len(s)-1)
+		rest = append(rest, s[:i]...)
+		rest = append(rest, s[i+1:]...)
+		for _, p := range permutations(rest) {
+			perms = append(perms, append([]string{s[i]}, p...))
+		}
+	}
+	return perms
+}
+
+func appendUnique(l []string, s string) []string {
+	for _, v := range l {
+		if v == s {
+			return l
+		}
+	}
+	return append(l, s)
+}
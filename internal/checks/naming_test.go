@@ -0,0 +1,181 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestNamingCheck(t *testing.T) {
+	strict := checks.NewNamingCheck(true, true, true, checks.Warning)
+
+	testCases := []checkTest{
+		{
+			description: "ignores alerting rules",
+			content:     "- alert: foo\n  expr: sum(foo) > 0\n",
+			checker:     strict,
+		},
+		{
+			description: "missing colons",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     checks.NewNamingCheck(false, false, false, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     "recording rule name should follow level:metric:operations naming convention",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "empty part",
+			content:     "- record: job::rate5m\n  expr: sum(rate(foo[5m])) by(job)\n",
+			checker:     checks.NewNamingCheck(false, false, false, checks.Bug),
+			problems: []checks.Problem{
+				{
+					Fragment: "job::rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     "recording rule name should follow level:metric:operations naming convention",
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "valid name",
+			content:     "- record: instance_path:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(path, instance)\n",
+			checker:     strict,
+		},
+		{
+			description: "valid ratio",
+			content: `- record: path:request_failures_per_requests:rate5m
+  expr: |
+    sum(rate(request_failures_total[5m])) by(path)
+    /
+    sum(rate(requests_total[5m])) by(path)
+`,
+			checker: strict,
+		},
+		{
+			description: "valid name using recording rule",
+			content:     "- record: job:requests:max_rate5m\n  expr: max(instance:requests:rate5m) by(job)\n",
+			checker:     checks.NewNamingCheck(true, false, true, checks.Warning),
+		},
+		{
+			description: "wrong metric",
+			content:     "- record: job:errors:rate5m\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker:     strict,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:errors:rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule metric "errors" doesn't match any metric used in the query: requests_total`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "wrong level",
+			content:     "- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(job, instance)\n",
+			checker:     strict,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:requests:rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule level "job" doesn't match labels preserved by the query, it should be "instance_job"`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "level is ignored for without()",
+			content:     "- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) without(instance)\n",
+			checker:     strict,
+		},
+		{
+			description: "wrong rate range",
+			content:     "- record: job:requests:rate5m\n  expr: sum(rate(requests_total[2m])) by(job)\n",
+			checker:     strict,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:requests:rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule operations "rate5m" should include rate2m since it's used in the query`,
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "job:requests:rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule operations "rate5m" include rate5m but it's not used in the query`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "missing aggregation",
+			content:     "- record: job:requests:rate5m\n  expr: avg(rate(requests_total[5m])) by(job)\n",
+			checker:     strict,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:requests:rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule operations "rate5m" should include avg since it's used in the query`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "repeated operation is reported once",
+			content:     "- record: job:requests:rate5m\n  expr: avg(rate(requests_total[5m])) by(job) / avg(rate(requests_total[5m])) by(job)\n",
+			checker:     strict,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:requests:rate5m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule operations "rate5m" should include avg since it's used in the query`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "repeated unused operation is reported once",
+			content:     "- record: job:requests:sum_rate1m_rate1m\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker:     strict,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:requests:sum_rate1m_rate1m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule operations "sum_rate1m_rate1m" should include rate5m since it's used in the query`,
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "job:requests:sum_rate1m_rate1m",
+					Lines:    []int{1},
+					Reporter: "promql/naming",
+					Text:     `recording rule operations "sum_rate1m_rate1m" include rate1m but it's not used in the query`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "mean is accepted for avg",
+			content:     "- record: job:requests:mean_rate5m\n  expr: avg(rate(requests_total[5m])) by(job)\n",
+			checker:     strict,
+		},
+		{
+			description: "only structure is checked by default",
+			content:     "- record: job:errors:rate5m\n  expr: sum(rate(requests_total[2m])) by(job, instance)\n",
+			checker:     checks.NewNamingCheck(false, false, false, checks.Warning),
+		},
+	}
+	runTests(t, testCases)
+}
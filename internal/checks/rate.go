@@ -49,13 +49,14 @@ func (c RateCheck) Check(rule parser.Rule) (problems []Problem) {
 		}
 	}
 
-	for _, problem := range c.checkNode(expr.Query, scrapeInterval) {
+	for _, problem := range c.checkNode(expr, expr.Query, scrapeInterval) {
 		problems = append(problems, Problem{
 			Fragment: problem.expr,
 			Lines:    expr.Lines(),
 			Reporter: RateCheckName,
 			Text:     problem.text,
 			Severity: problem.severity,
+			Fix:      problem.fix,
 		})
 	}
 
@@ -71,7 +72,7 @@ func (c RateCheck) getScrapeInterval() (interval time.Duration, err error) {
 	return cfg.Global.ScrapeInterval, nil
 }
 
-func (c RateCheck) checkNode(node *parser.PromQLNode, scrapeInterval time.Duration) (problems []exprProblem) {
+func (c RateCheck) checkNode(expr parser.PromQLExpr, node *parser.PromQLNode, scrapeInterval time.Duration) (problems []exprProblem) {
 	if n, ok := node.Node.(*promParser.Call); ok && (n.Func.Name == "rate" || n.Func.Name == "irate") {
 		var minIntervals int
 		var recIntervals int
@@ -90,6 +91,7 @@ func (c RateCheck) checkNode(node *parser.PromQLNode, scrapeInterval time.Durati
 						expr:     node.Expr,
 						text:     fmt.Sprintf("duration for %s() must be at least %d x scrape_interval, %s is using %s scrape_interval", n.Func.Name, minIntervals, c.name, promapi.HumanizeDuration(scrapeInterval)),
 						severity: Bug,
+						fix:      rangeFix(expr, m, scrapeInterval*time.Duration(minIntervals)),
 					}
 					problems = append(problems, p)
 				} else if m.Range < scrapeInterval*time.Duration(recIntervals) {
@@ -97,6 +99,7 @@ func (c RateCheck) checkNode(node *parser.PromQLNode, scrapeInterval time.Durati
 						expr:     node.Expr,
 						text:     fmt.Sprintf("duration for %s() is recommended to be at least %d x scrape_interval, %s is using %s scrape_interval", n.Func.Name, recIntervals, c.name, promapi.HumanizeDuration(scrapeInterval)),
 						severity: Warning,
+						fix:      rangeFix(expr, m, scrapeInterval*time.Duration(recIntervals)),
 					}
 					problems = append(problems, p)
 				}
@@ -105,7 +108,7 @@ func (c RateCheck) checkNode(node *parser.PromQLNode, scrapeInterval time.Durati
 	}
 
 	for _, child := range node.Children {
-		problems = append(problems, c.checkNode(child, scrapeInterval)...)
+		problems = append(problems, c.checkNode(expr, child, scrapeInterval)...)
 	}
 
 	return
@@ -59,6 +59,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for rate() must be at least 2 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[1m]",
+								New:       "[2m]",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -73,6 +84,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for rate() is recommended to be at least 4 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[3m]",
+								New:       "[4m]",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -92,6 +114,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for irate() must be at least 2 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[1m]",
+								New:       "[2m]",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -106,6 +139,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for irate() is recommended to be at least 3 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[2m]",
+								New:       "[3m]",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -130,6 +174,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for rate() is recommended to be at least 4 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[3m]",
+								New:       "[4m]",
+							},
+						},
+					},
 				},
 				{
 					Fragment: "rate(bar[1m])",
@@ -137,6 +192,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for rate() must be at least 2 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[1m]",
+								New:       "[2m]",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -198,6 +264,17 @@ func TestRateCheck(t *testing.T) {
 					Reporter: "promql/rate",
 					Text:     "duration for irate() is recommended to be at least 3 x scrape_interval, prom is using 1m scrape_interval",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "[2m]",
+								New:       "[3m]",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/alertmanager"
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	RoutingCheckName = "alerts/routing"
+)
+
+func NewRoutingCheck(am *alertmanager.Config, severity Severity) RoutingCheck {
+	return RoutingCheck{am: am, severity: severity}
+}
+
+// RoutingCheck uses static labels of alerting rules to verify that alerts
+// would be routed to a receiver other than the default one, labels with
+// values generated by templates are ignored and if any of those is used by
+// routes then we can't tell where alerts would be sent
+type RoutingCheck struct {
+	am       *alertmanager.Config
+	severity Severity
+}
+
+func (c RoutingCheck) String() string {
+	return RoutingCheckName
+}
+
+func (c RoutingCheck) Check(rule parser.Rule) (problems []Problem) {
+	if rule.AlertingRule == nil {
+		return nil
+	}
+
+	lset := map[string]string{
+		model.AlertNameLabel: rule.AlertingRule.Alert.Value.Value,
+	}
+	// routing of alerts with templated labels used by routes depends on
+	// values we don't know
+	var isTemplated bool
+	if rule.AlertingRule.Labels != nil {
+		for _, item := range rule.AlertingRule.Labels.Items {
+			if strings.Contains(item.Value.Value, "{{") {
+				if c.am.UsesLabel(item.Key.Value) {
+					isTemplated = true
+				}
+				continue
+			}
+			lset[item.Key.Value] = item.Value.Value
+			problems = append(problems, c.checkValue(item)...)
+		}
+	}
+	if len(problems) > 0 || isTemplated {
+		return problems
+	}
+
+	routes := c.am.Match(lset)
+	if !c.am.IsDefault(routes) {
+		return nil
+	}
+
+	fragment := fmt.Sprintf("%s: %s", rule.AlertingRule.Alert.Key.Value, rule.AlertingRule.Alert.Value.Value)
+	lines := rule.AlertingRule.Alert.Lines()
+	if rule.AlertingRule.Labels != nil {
+		fragment = fmt.Sprintf("%s:", rule.AlertingRule.Labels.Key.Value)
+		lines = rule.AlertingRule.Labels.Key.Position.Lines
+	}
+	problems = append(problems, Problem{
+		Fragment: fragment,
+		Lines:    lines,
+		Reporter: RoutingCheckName,
+		Text:     fmt.Sprintf("alert labels don't match any Alertmanager route, it will only be sent to the default %q receiver", routes[0].Receiver),
+		Severity: c.severity,
+	})
+	return problems
+}
+
+// checkValue reports label values that don't match any matcher for this
+// label used in the routing tree
+func (c RoutingCheck) checkValue(item *parser.YamlKeyValue) (problems []Problem) {
+	matchers := c.am.LabelMatchers(item.Key.Value)
+	if len(matchers) == 0 {
+		return nil
+	}
+
+	known := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Matches(item.Value.Value) {
+			return nil
+		}
+		known = append(known, m.String())
+	}
+
+	problems = append(problems, Problem{
+		Fragment: fmt.Sprintf("%s: %s", item.Key.Value, item.Value.Value),
+		Lines:    item.Lines(),
+		Reporter: RoutingCheckName,
+		Text: fmt.Sprintf("%s label value %q doesn't match any Alertmanager route, routes are using: %s",
+			item.Key.Value, item.Value.Value, strings.Join(known, ", ")),
+		Severity: c.severity,
+	})
+	return problems
+}
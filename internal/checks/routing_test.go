@@ -0,0 +1,98 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/alertmanager"
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestRoutingCheck(t *testing.T) {
+	am, err := alertmanager.Parse([]byte(`
+route:
+  receiver: default
+  routes:
+  - match:
+      alertname: Watchdog
+    receiver: deadman
+  - matchers: [ 'team=~"db|cache"' ]
+    receiver: storage
+  - match:
+      team: web
+    receiver: web
+receivers:
+- name: default
+- name: deadman
+- name: storage
+- name: web
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []checkTest{
+		{
+			description: "ignores recording rules",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     checks.NewRoutingCheck(am, checks.Warning),
+		},
+		{
+			description: "routed by label",
+			content:     "- alert: Foo\n  expr: up == 0\n  labels:\n    team: cache\n",
+			checker:     checks.NewRoutingCheck(am, checks.Warning),
+		},
+		{
+			description: "routed by alertname",
+			content:     "- alert: Watchdog\n  expr: vector(1)\n",
+			checker:     checks.NewRoutingCheck(am, checks.Warning),
+		},
+		{
+			description: "no labels",
+			content:     "- alert: Foo\n  expr: up == 0\n",
+			checker:     checks.NewRoutingCheck(am, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "alert: Foo",
+					Lines:    []int{1},
+					Reporter: "alerts/routing",
+					Text:     `alert labels don't match any Alertmanager route, it will only be sent to the default "default" receiver`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "labels without routing labels",
+			content:     "- alert: Foo\n  expr: up == 0\n  labels:\n    severity: critical\n    summary: '{{ $value }}'\n",
+			checker:     checks.NewRoutingCheck(am, checks.Bug),
+			problems: []checks.Problem{
+				{
+					Fragment: "labels:",
+					Lines:    []int{3},
+					Reporter: "alerts/routing",
+					Text:     `alert labels don't match any Alertmanager route, it will only be sent to the default "default" receiver`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "templated routing label",
+			content:     "- alert: Foo\n  expr: up == 0\n  labels:\n    severity: critical\n    team: '{{ $labels.team }}'\n",
+			checker:     checks.NewRoutingCheck(am, checks.Bug),
+		},
+		{
+			description: "unknown label value",
+			content:     "- alert: Foo\n  expr: up == 0\n  labels:\n    severity: critical\n    team: dbs\n",
+			checker:     checks.NewRoutingCheck(am, checks.Warning),
+			problems: []checks.Problem{
+				{
+					Fragment: "team: dbs",
+					Lines:    []int{5},
+					Reporter: "alerts/routing",
+					Text:     `team label value "dbs" doesn't match any Alertmanager route, routes are using: team="web", team=~"db|cache"`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,149 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/template"
+)
+
+const (
+	TemplateCheckName = "alerts/template"
+)
+
+var (
+	// strip "template: __alert_foo:1: " prefix from parse errors
+	templateErrRe = regexp.MustCompile(`^template: __alert_.*?:\d+: `)
+
+	// find labels referenced as $labels.name or index $labels "name"
+	templateLabelRe = regexp.MustCompile(`\$labels\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+	templateIndexRe = regexp.MustCompile(`index\s+\$labels\s+"([a-zA-Z_][a-zA-Z0-9_]*)"`)
+
+	// same variables Prometheus defines for alert templates
+	templateDefs = []string{
+		"{{$labels := .Labels}}",
+		"{{$externalLabels := .ExternalLabels}}",
+		"{{$value := .Value}}",
+	}
+)
+
+func NewTemplateCheck() TemplateCheck {
+	return TemplateCheck{}
+}
+
+type TemplateCheck struct{}
+
+func (c TemplateCheck) String() string {
+	return TemplateCheckName
+}
+
+func (c TemplateCheck) Check(rule parser.Rule) (problems []Problem) {
+	if rule.AlertingRule == nil {
+		return nil
+	}
+
+	if rule.AlertingRule.Labels != nil {
+		for _, label := range rule.AlertingRule.Labels.Items {
+			problems = append(problems, c.checkItem(rule, "label", label)...)
+		}
+	}
+
+	if rule.AlertingRule.Annotations != nil {
+		for _, annotation := range rule.AlertingRule.Annotations.Items {
+			problems = append(problems, c.checkItem(rule, "annotation", annotation)...)
+		}
+	}
+
+	return problems
+}
+
+func (c TemplateCheck) checkItem(rule parser.Rule, kind string, item *parser.YamlKeyValue) (problems []Problem) {
+	fragment := fmt.Sprintf("%s: %s", item.Key.Value, item.Value.Value)
+
+	if err := checkTemplateSyntax(rule.AlertingRule.Alert.Value.Value, item.Value.Value); err != nil {
+		problems = append(problems, Problem{
+			Fragment: fragment,
+			Lines:    item.Lines(),
+			Reporter: TemplateCheckName,
+			Text:     fmt.Sprintf("template parse error in %q %s: %s", item.Key.Value, kind, err),
+			Severity: Fatal,
+		})
+		return problems
+	}
+
+	if rule.AlertingRule.Expr.SyntaxError != nil {
+		return problems
+	}
+
+	ol := queryLabels(rule.AlertingRule.Expr.Query)
+	for _, name := range templateLabels(item.Value.Value) {
+		if ok, removedBy := ol.canHave(name); !ok {
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    item.Lines(),
+				Reporter: TemplateCheckName,
+				Text:     fmt.Sprintf("template in %q %s is using %q label but the query removes it: %s", item.Key.Value, kind, name, removedBy),
+				Severity: Warning,
+			})
+		}
+	}
+
+	return problems
+}
+
+// templateLabels returns names of all labels referenced in a template
+// via $labels.name or index $labels "name"
+func templateLabels(text string) (names []string) {
+	seen := map[string]struct{}{}
+	for _, re := range []*regexp.Regexp{templateLabelRe, templateIndexRe} {
+		for _, match := range re.FindAllStringSubmatch(text, -1) {
+			if _, ok := seen[match[1]]; ok {
+				continue
+			}
+			seen[match[1]] = struct{}{}
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// checkTemplateSyntax parses given text the same way Prometheus does when
+// loading alerting rules
+func checkTemplateSyntax(name, text string) error {
+	data := template.AlertTemplateData(map[string]string{}, map[string]string{}, 0)
+	tmpl := template.NewTemplateExpander(
+		context.Background(),
+		strings.Join(append(templateDefs, text), ""),
+		"__alert_"+name,
+		data,
+		model.Time(time.Now().UnixNano()/int64(time.Millisecond)),
+		nil,
+		nil,
+	)
+	if err := tmpl.ParseTest(); err != nil {
+		return fmt.Errorf("%s", templateErrRe.ReplaceAllString(err.Error(), ""))
+	}
+	return nil
+}
+
+// expandTemplate renders given text the same way Prometheus does when sending
+// alerts, using given series labels and value
+func expandTemplate(name, text string, labels map[string]string, value float64) (string, error) {
+	data := template.AlertTemplateData(labels, map[string]string{}, value)
+	tmpl := template.NewTemplateExpander(
+		context.Background(),
+		strings.Join(append(templateDefs, text), ""),
+		"__alert_"+name,
+		data,
+		model.Time(time.Now().UnixNano()/int64(time.Millisecond)),
+		nil,
+		nil,
+	)
+	return tmpl.Expand()
+}
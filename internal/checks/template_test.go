@@ -0,0 +1,233 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestTemplateCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "skips recording rule",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    foo: '{{ $value }'\n",
+			checker:     checks.NewTemplateCheck(),
+		},
+		{
+			description: "valid templates",
+			content: `- alert: Foo Is Down
+  expr: up{job="foo"} == 0
+  labels:
+    severity: critical
+    instance: '{{ $labels.instance }}'
+  annotations:
+    summary: '{{ $labels.instance }} on {{ $externalLabels.cluster }} is down'
+    value: '{{ $value | humanize }}'
+    dashboard: '{{ "up" | query | first | value | humanizePercentage }}'
+`,
+			checker: checks.NewTemplateCheck(),
+		},
+		{
+			description: "unclosed action in label",
+			content: `- alert: Foo Is Down
+  expr: up{job="foo"} == 0
+  labels:
+    instance: '{{ $labels.instance }'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "instance: {{ $labels.instance }",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     `template parse error in "instance" label: unexpected "}" in operand`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "unknown function in annotation",
+			content: `- alert: Foo Is Down
+  expr: up{job="foo"} == 0
+  annotations:
+    summary: ok
+    value: |
+      Current value:
+      {{ humanise $value }}
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "value: Current value:\n{{ humanise $value }}\n",
+					Lines:    []int{5, 6, 7},
+					Reporter: "alerts/template",
+					Text:     `template parse error in "value" annotation: function "humanise" not defined`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "undefined variable",
+			content: `- alert: Foo Is Down
+  expr: up{job="foo"} == 0
+  annotations:
+    summary: '{{ $label.instance }}'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "summary: {{ $label.instance }}",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     `template parse error in "summary" annotation: undefined variable "$label"`,
+					Severity: checks.Fatal,
+				},
+			},
+		},
+		{
+			description: "label removed by aggregation",
+			content: `- alert: Foo Is Down
+  expr: sum(up{job="foo"}) by(job) == 0
+  annotations:
+    summary: '{{ $labels.instance }} on {{ $labels.job }} is down'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "summary: {{ $labels.instance }} on {{ $labels.job }} is down",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     "template in \"summary\" annotation is using \"instance\" label but the query removes it: sum by(job) (up{job=\"foo\"})",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "label removed by without",
+			content: `- alert: Foo Is Down
+  expr: sum(up{job="foo"}) without(instance) == 0
+  labels:
+    instance: '{{ index $labels "instance" }}'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "instance: {{ index $labels \"instance\" }}",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     "template in \"instance\" label is using \"instance\" label but the query removes it: sum without(instance) (up{job=\"foo\"})",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "label kept by aggregation",
+			content: `- alert: Foo Is Down
+  expr: sum(up{job="foo"}) without(job) == 0
+  annotations:
+    summary: '{{ $labels.instance }} is down'
+`,
+			checker: checks.NewTemplateCheck(),
+		},
+		{
+			description: "label added by label_replace",
+			content: `- alert: Foo Is Down
+  expr: label_replace(sum(up) by(job), "instance", "$1", "job", "(.+)") == 0
+  annotations:
+    summary: '{{ $labels.instance }} is down'
+`,
+			checker: checks.NewTemplateCheck(),
+		},
+		{
+			description: "label removed by on()",
+			content: `- alert: Foo Is Down
+  expr: up{job="foo"} == on(job) sum(up) by(job)
+  annotations:
+    summary: '{{ $labels.instance }} is down'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "summary: {{ $labels.instance }} is down",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     "template in \"summary\" annotation is using \"instance\" label but the query removes it: up{job=\"foo\"} == on(job) sum(up) by(job)",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "label added by group_left",
+			content: `- alert: Foo Is Down
+  expr: sum(up) by(job) * on(job) group_left(instance) up
+  annotations:
+    summary: '{{ $labels.instance }} is down'
+`,
+			checker: checks.NewTemplateCheck(),
+		},
+		{
+			description: "label only on one side of or",
+			content: `- alert: Foo Is Down
+  expr: sum(up) by(job) == 0 or sum(up) by(job, instance) == 0
+  annotations:
+    summary: '{{ $labels.instance }} is down'
+`,
+			checker: checks.NewTemplateCheck(),
+		},
+		{
+			description: "absent() only keeps equality matchers",
+			content: `- alert: Foo Is Missing
+  expr: absent(up{job="foo", instance=~".+"})
+  annotations:
+    summary: '{{ $labels.job }} {{ $labels.instance }} is missing'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "summary: {{ $labels.job }} {{ $labels.instance }} is missing",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     "template in \"summary\" annotation is using \"instance\" label but the query removes it: absent(up{job=\"foo\", instance=~\".+\"})",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "histogram_quantile removes le",
+			content: `- alert: Foo Is Slow
+  expr: histogram_quantile(0.9, rate(http_request_duration_seconds_bucket[5m])) > 1
+  annotations:
+    summary: '{{ $labels.instance }} {{ $labels.le }}'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "summary: {{ $labels.instance }} {{ $labels.le }}",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     "template in \"summary\" annotation is using \"le\" label but the query removes it: histogram_quantile(0.9, rate(http_request_duration_seconds_bucket[5m]))",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "vector() has no labels",
+			content: `- alert: Foo Is Down
+  expr: vector(1) > 0
+  annotations:
+    summary: '{{ $labels.job }}'
+`,
+			checker: checks.NewTemplateCheck(),
+			problems: []checks.Problem{
+				{
+					Fragment: "summary: {{ $labels.job }}",
+					Lines:    []int{4},
+					Reporter: "alerts/template",
+					Text:     "template in \"summary\" annotation is using \"job\" label but the query removes it: vector(1)",
+					Severity: checks.Warning,
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
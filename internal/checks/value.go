@@ -37,6 +37,7 @@ func (c ValueCheck) Check(rule parser.Rule) (problems []Problem) {
 					Reporter: ValueCheckName,
 					Text:     fmt.Sprintf("using %s in labels will generate a new alert on every value change, move it to annotations", token),
 					Severity: c.severity,
+					Fix:      moveToAnnotationsFix(rule, label),
 				})
 			}
 			if token, ok := hasValue(label.Value.Value); ok {
@@ -46,6 +47,7 @@ func (c ValueCheck) Check(rule parser.Rule) (problems []Problem) {
 					Reporter: ValueCheckName,
 					Text:     fmt.Sprintf("using %s in labels will generate a new alert on every value change, move it to annotations", token),
 					Severity: c.severity,
+					Fix:      moveToAnnotationsFix(rule, label),
 				})
 			}
 		}
@@ -70,3 +72,57 @@ func hasValue(s string) (string, bool) {
 	}
 	return "", false
 }
+
+// moveToAnnotationsFix returns a fix that will move given label to annotations,
+// annotations block will be created if it's missing
+func moveToAnnotationsFix(rule parser.Rule, label *parser.YamlKeyValue) *Fix {
+	labels := rule.AlertingRule.Labels
+	labelsLine, _ := lineRange(labels.Key.Position.Lines)
+	first, last := lineRange(label.Lines())
+	if first <= labelsLine {
+		// labels are using flow style, we can't move lines around
+		return nil
+	}
+
+	fix := Fix{}
+	if annotations := rule.AlertingRule.Annotations; annotations != nil {
+		annotationsLine, _ := lineRange(annotations.Key.Position.Lines)
+		if len(annotations.Items) == 0 {
+			return nil
+		}
+		itemLine, _ := lineRange(annotations.Items[0].Lines())
+		if itemLine <= annotationsLine {
+			return nil
+		}
+		fix.Edits = append(fix.Edits, Edit{
+			Op:         CopyLines,
+			FirstLine:  first,
+			LastLine:   last,
+			Target:     annotationsLine,
+			IndentFrom: itemLine,
+		})
+	} else {
+		_, ruleLast := lineRange(rule.Lines())
+		fix.Edits = append(fix.Edits,
+			Edit{
+				Op:         InsertLines,
+				New:        "annotations:",
+				Target:     ruleLast,
+				IndentFrom: labelsLine,
+			},
+			Edit{
+				Op:        CopyLines,
+				FirstLine: first,
+				LastLine:  last,
+				Target:    ruleLast,
+			},
+		)
+	}
+	fix.Edits = append(fix.Edits, Edit{
+		Op:        DeleteLines,
+		FirstLine: first,
+		LastLine:  last,
+	})
+
+	return &fix
+}
@@ -34,6 +34,27 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     5,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  5,
+								Target:    5,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  5,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -48,6 +69,27 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     5,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  5,
+								Target:    5,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  5,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -62,6 +104,27 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Fatal,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     5,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  5,
+								Target:    5,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  5,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -76,6 +139,27 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     6,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  6,
+								Target:    6,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  6,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -90,6 +174,27 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     6,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  6,
+								Target:    6,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  6,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -104,6 +209,27 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using .Value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     5,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  5,
+								Target:    5,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  5,
+							},
+						},
+					},
 				},
 			},
 		},
@@ -118,6 +244,71 @@ func TestValueCheck(t *testing.T) {
 					Reporter: "alerts/value",
 					Text:     "using .Value in labels will generate a new alert on every value change, move it to annotations",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.InsertLines,
+								New:        "annotations:",
+								Target:     5,
+								IndentFrom: 3,
+							},
+							{
+								Op:        checks.CopyLines,
+								FirstLine: 5,
+								LastLine:  5,
+								Target:    5,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 5,
+								LastLine:  5,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "{{ $value }} in label value with annotations",
+			content:     "- alert: foo\n  expr: sum(foo)\n  labels:\n    foo: bar {{ $value }}\n  annotations:\n      summary: foo\n",
+			checker:     checks.NewValueCheck(checks.Bug),
+			problems: []checks.Problem{
+				{
+					Fragment: "bar {{ $value }}",
+					Lines:    []int{4},
+					Reporter: "alerts/value",
+					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
+					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:         checks.CopyLines,
+								FirstLine:  4,
+								LastLine:   4,
+								Target:     5,
+								IndentFrom: 6,
+							},
+							{
+								Op:        checks.DeleteLines,
+								FirstLine: 4,
+								LastLine:  4,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "{{ $value }} in flow style labels",
+			content:     "- alert: foo\n  expr: sum(foo)\n  labels: {foo: 'bar {{ $value }}'}\n",
+			checker:     checks.NewValueCheck(checks.Bug),
+			problems: []checks.Problem{
+				{
+					Fragment: "bar {{ $value }}",
+					Lines:    []int{3},
+					Reporter: "alerts/value",
+					Text:     "using $value in labels will generate a new alert on every value change, move it to annotations",
+					Severity: checks.Bug,
 				},
 			},
 		},
@@ -47,20 +47,21 @@ func (c WithoutCheck) Check(rule parser.Rule) (problems []Problem) {
 		}
 	}
 
-	for _, problem := range c.checkNode(expr.Query) {
+	for _, problem := range c.checkNode(expr, expr.Query) {
 		problems = append(problems, Problem{
 			Fragment: problem.expr,
 			Lines:    expr.Lines(),
 			Reporter: WithoutCheckName,
 			Text:     problem.text,
 			Severity: c.severity,
+			Fix:      problem.fix,
 		})
 	}
 
 	return
 }
 
-func (c WithoutCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem) {
+func (c WithoutCheck) checkNode(expr parser.PromQLExpr, node *parser.PromQLNode) (problems []exprProblem) {
 	if n, ok := node.Node.(*promParser.AggregateExpr); ok && n.Without && n.Op != promParser.TOPK {
 		var found bool
 		for _, g := range n.Grouping {
@@ -74,6 +75,7 @@ func (c WithoutCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem
 			problems = append(problems, exprProblem{
 				expr: node.Expr,
 				text: fmt.Sprintf("%s label is required and should be preserved when aggregating %q rules, remove %s from without()", c.label, c.nameRegex, c.label),
+				fix:  groupingFix(expr, n, c.label, false),
 			})
 		}
 
@@ -81,6 +83,7 @@ func (c WithoutCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem
 			problems = append(problems, exprProblem{
 				expr: node.Expr,
 				text: fmt.Sprintf("%s label should be removed when aggregating %q rules, use without(%s, ...)", c.label, c.nameRegex, c.label),
+				fix:  groupingFix(expr, n, c.label, true),
 			})
 		}
 
@@ -92,7 +95,7 @@ func (c WithoutCheck) checkNode(node *parser.PromQLNode) (problems []exprProblem
 	}
 
 	for _, child := range node.Children {
-		problems = append(problems, c.checkNode(child)...)
+		problems = append(problems, c.checkNode(expr, child)...)
 	}
 
 	return
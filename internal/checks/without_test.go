@@ -35,6 +35,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, remove job from without()",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(instance, job)",
+								New:       "without (instance)",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -49,6 +60,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, remove job from without()",
 					Severity: checks.Bug,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(instance, job)",
+								New:       "without (instance)",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -63,6 +85,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     "job label should be removed when aggregating \"^.+$\" rules, use without(job, ...)",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(instance)",
+								New:       "without (instance, job)",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -87,6 +120,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, remove job from without()",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(job)",
+								New:       "without ()",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -106,6 +150,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     `instance label should be removed when aggregating "^.+$" rules, use without(instance, ...)`,
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(bar)",
+								New:       "without (bar, instance)",
+							},
+						},
+					},
 				},
 				{
 					Fragment: "sum without(foo) (foo)",
@@ -113,6 +168,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     `instance label should be removed when aggregating "^.+$" rules, use without(instance, ...)`,
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(foo)",
+								New:       "without (foo, instance)",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -127,6 +193,17 @@ func TestWithoutCheck(t *testing.T) {
 					Reporter: "promql/without",
 					Text:     "job label is required and should be preserved when aggregating \"^.+$\" rules, remove job from without()",
 					Severity: checks.Warning,
+					Fix: &checks.Fix{
+						Edits: []checks.Edit{
+							{
+								Op:        checks.ReplaceText,
+								FirstLine: 2,
+								LastLine:  2,
+								Old:       "without(job)",
+								New:       "without ()",
+							},
+						},
+					},
 				},
 			},
 		},
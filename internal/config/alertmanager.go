@@ -0,0 +1,25 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/alertmanager"
+)
+
+type AlertmanagerConfig struct {
+	Config string `hcl:"config"`
+
+	config *alertmanager.Config
+}
+
+// load parses Alertmanager configuration file, it's only done once when
+// pint config is loaded and the result is shared by all checks
+func (ac *AlertmanagerConfig) load() (err error) {
+	ac.config, err = alertmanager.Load(ac.Config)
+	return err
+}
+
+func (ac *AlertmanagerConfig) getConfig() *alertmanager.Config {
+	if ac == nil {
+		return nil
+	}
+	return ac.config
+}
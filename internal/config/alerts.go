@@ -1,9 +1,22 @@
 package config
 
+import (
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
 type AlertsSettings struct {
-	Range   string `hcl:"range"`
-	Step    string `hcl:"step"`
-	Resolve string `hcl:"resolve"`
+	Range       string   `hcl:"range"`
+	Step        string   `hcl:"step"`
+	Resolve     string   `hcl:"resolve"`
+	Labels      []string `hcl:"labels,optional"`
+	MaxAlerts   int      `hcl:"maxAlerts,optional"`
+	MaxFlapping int      `hcl:"maxFlapping,optional"`
+	// DefaultRoute enables raising severity when alerts only match the
+	// default Alertmanager route
+	DefaultRoute bool   `hcl:"defaultRoute,optional"`
+	Severity     string `hcl:"severity,optional"`
 }
 
 func (as AlertsSettings) validate() error {
@@ -22,5 +35,24 @@ func (as AlertsSettings) validate() error {
 			return err
 		}
 	}
+	if as.MaxAlerts < 0 {
+		return fmt.Errorf("maxAlerts value must be >= 0")
+	}
+	if as.MaxFlapping < 0 {
+		return fmt.Errorf("maxFlapping value must be >= 0")
+	}
+	if as.Severity != "" {
+		if _, err := checks.ParseSeverity(as.Severity); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func (as AlertsSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if as.Severity != "" {
+		sev, _ := checks.ParseSeverity(as.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/graph"
 	"github.com/cloudflare/pint/internal/parser"
 
 	"github.com/hashicorp/hcl/v2/hclsimple"
@@ -16,11 +17,12 @@ import (
 )
 
 type Config struct {
-	CI         *CI                `hcl:"ci,block"`
-	Repository *Repository        `hcl:"repository,block"`
-	Prometheus []PrometheusConfig `hcl:"prometheus,block"`
-	Checks     *Checks            `hcl:"checks,block"`
-	Rules      []Rule             `hcl:"rule,block"`
+	CI           *CI                 `hcl:"ci,block"`
+	Repository   *Repository         `hcl:"repository,block"`
+	Prometheus   []PrometheusConfig  `hcl:"prometheus,block"`
+	Alertmanager *AlertmanagerConfig `hcl:"alertmanager,block"`
+	Checks       *Checks             `hcl:"checks,block"`
+	Rules        []Rule              `hcl:"rule,block"`
 }
 
 func (cfg *Config) SetDisabledChecks(l []string) {
@@ -46,6 +48,12 @@ func (cfg *Config) SetDisabledChecks(l []string) {
 	}
 }
 
+// IsCheckEnabled returns true if given check wasn't disabled, it's used for
+// checks that don't run per rule
+func (cfg Config) IsCheckEnabled(name string) bool {
+	return isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, name, parser.Rule{})
+}
+
 func (cfg Config) String() string {
 	content, _ := json.MarshalIndent(cfg, "", "  ")
 	return string(content)
@@ -58,14 +66,27 @@ func (cfg Config) GetChecksForRule(path string, r parser.Rule) []checks.RuleChec
 		enabled = append(enabled, checks.NewSyntaxCheck())
 	}
 
+	if isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, checks.TemplateCheckName, r) {
+		enabled = append(enabled, checks.NewTemplateCheck())
+	}
+
+	if isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, checks.HistogramCheckName, r) {
+		enabled = append(enabled, checks.NewHistogramCheck())
+	}
+
+	if isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, checks.ForCheckName, r) {
+		enabled = append(enabled, checks.NewForValueCheck())
+	}
+
 	proms := []PrometheusConfig{}
 	for _, prom := range cfg.Prometheus {
 		if prom.isEnabledForPath(path) {
 			proms = append(proms, prom)
 		}
 	}
+
 	for _, rule := range cfg.Rules {
-		for _, c := range rule.resolveChecks(path, r, cfg.Checks.Enabled, cfg.Checks.Disabled, proms) {
+		for _, c := range rule.resolveChecks(path, r, cfg.Checks.Enabled, cfg.Checks.Disabled, proms, cfg.Alertmanager.getConfig()) {
 			if r.HasComment(fmt.Sprintf("disable %s", removeRedundantSpaces(c.String()))) {
 				log.Debug().
 					Str("path", path).
@@ -82,17 +103,72 @@ func (cfg Config) GetChecksForRule(path string, r parser.Rule) []checks.RuleChec
 	for _, e := range enabled {
 		el = append(el, fmt.Sprintf("%v", e))
 	}
-	name := "unknown"
-	if r.AlertingRule != nil {
-		name = r.AlertingRule.Alert.Value.Value
-	} else if r.RecordingRule != nil {
-		name = r.RecordingRule.Record.Value.Value
+	name := r.Name()
+	if name == "" {
+		name = "unknown"
 	}
 	log.Debug().Strs("enabled", el).Str("path", path).Str("rule", name).Msg("Configured checks for rule")
 
 	return enabled
 }
 
+// GetChecksForRuleSet returns checks that need to know about all other
+// scanned rules, using a dependency graph built from those rules
+func (cfg Config) GetChecksForRuleSet(path string, r parser.Rule, g *graph.Graph) []checks.RuleChecker {
+	enabled := []checks.RuleChecker{}
+
+	for _, rule := range cfg.Rules {
+		for _, c := range rule.resolveRuleSetChecks(path, r, g, cfg.Checks.Enabled, cfg.Checks.Disabled) {
+			// the first matching rule block wins, running the same check
+			// twice would only report every problem twice
+			var isDuplicate bool
+			for _, e := range enabled {
+				if e.String() == c.String() {
+					isDuplicate = true
+				}
+			}
+			if !isDuplicate {
+				enabled = append(enabled, c)
+			}
+		}
+	}
+
+	return enabled
+}
+
+func (cfg Config) GetChecksForFile(path string) []checks.FileChecker {
+	enabled := []checks.FileChecker{}
+
+	if isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, checks.GroupCheckName, parser.Rule{}) {
+		enabled = append(enabled, checks.NewGroupCheck())
+	}
+
+	for _, rule := range cfg.Rules {
+		for _, c := range rule.resolveFileChecks(path, cfg.Checks.Enabled, cfg.Checks.Disabled) {
+			// multiple rule blocks can enable the same check with identical
+			// settings, running it twice would only report every problem
+			// twice, checks with different settings are all kept
+			var isDuplicate bool
+			for _, e := range enabled {
+				if e == c {
+					isDuplicate = true
+				}
+			}
+			if !isDuplicate {
+				enabled = append(enabled, c)
+			}
+		}
+	}
+
+	el := []string{}
+	for _, e := range enabled {
+		el = append(el, fmt.Sprintf("%v", e))
+	}
+	log.Debug().Strs("enabled", el).Str("path", path).Msg("Configured checks for file")
+
+	return enabled
+}
+
 func Load(path string) (cfg Config, err error) {
 	cfg = Config{
 		CI: &CI{
@@ -126,6 +202,30 @@ func Load(path string) (cfg Config, err error) {
 		}
 	}
 
+	if cfg.Repository != nil && cfg.Repository.GitHub != nil {
+		if cfg.Repository.GitHub.BaseURI == "" {
+			cfg.Repository.GitHub.BaseURI = "https://api.github.com"
+		}
+		if cfg.Repository.GitHub.Timeout == "" {
+			cfg.Repository.GitHub.Timeout = "1m"
+		}
+		if err = cfg.Repository.GitHub.validate(); err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.Repository != nil && cfg.Repository.GitLab != nil {
+		if cfg.Repository.GitLab.CodeQuality == "" {
+			cfg.Repository.GitLab.CodeQuality = "gl-code-quality-report.json"
+		}
+		if cfg.Repository.GitLab.Timeout == "" {
+			cfg.Repository.GitLab.Timeout = "1m"
+		}
+		if err = cfg.Repository.GitLab.validate(); err != nil {
+			return cfg, err
+		}
+	}
+
 	if cfg.Checks != nil {
 		if err = cfg.Checks.validate(); err != nil {
 			return cfg, err
@@ -138,6 +238,12 @@ func Load(path string) (cfg Config, err error) {
 		}
 	}
 
+	if cfg.Alertmanager != nil {
+		if err = cfg.Alertmanager.load(); err != nil {
+			return cfg, err
+		}
+	}
+
 	for _, rule := range cfg.Rules {
 		if rule.Match != nil {
 			if err = rule.Match.validate(); err != nil {
@@ -175,6 +281,24 @@ func Load(path string) (cfg Config, err error) {
 			}
 		}
 
+		if rule.Counter != nil {
+			if err = rule.Counter.validate(); err != nil {
+				return cfg, err
+			}
+		}
+
+		if rule.For != nil {
+			if err = rule.For.validate(); err != nil {
+				return cfg, err
+			}
+		}
+
+		if rule.Histogram != nil {
+			if err = rule.Histogram.validate(); err != nil {
+				return cfg, err
+			}
+		}
+
 		if rule.Series != nil {
 			if err = rule.Series.validate(); err != nil {
 				return cfg, err
@@ -199,6 +323,39 @@ func Load(path string) (cfg Config, err error) {
 			}
 
 		}
+
+		if rule.Format != nil {
+			if err = rule.Format.validate(); err != nil {
+				return cfg, err
+			}
+		}
+
+		if rule.Routing != nil {
+			if err = rule.Routing.validate(); err != nil {
+				return cfg, err
+			}
+			if cfg.Alertmanager == nil {
+				return cfg, fmt.Errorf("routing check requires alertmanager config block")
+			}
+		}
+
+		if rule.Naming != nil {
+			if err = rule.Naming.validate(); err != nil {
+				return cfg, err
+			}
+		}
+
+		if rule.Duplicate != nil {
+			if err = rule.Duplicate.validate(); err != nil {
+				return cfg, err
+			}
+		}
+
+		if rule.Dependency != nil {
+			if err = rule.Dependency.validate(); err != nil {
+				return cfg, err
+			}
+		}
 	}
 
 	return cfg, nil
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type CounterSettings struct {
+	Severity string `hcl:"severity,optional"`
+}
+
+func (cs CounterSettings) validate() error {
+	if cs.Severity != "" {
+		if _, err := checks.ParseSeverity(cs.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cs CounterSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if cs.Severity != "" {
+		sev, _ := checks.ParseSeverity(cs.Severity)
+		return sev
+	}
+	return fallback
+}
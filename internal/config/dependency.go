@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type DependencySettings struct {
+	Severity string `hcl:"severity,optional"`
+}
+
+func (ds DependencySettings) validate() error {
+	if ds.Severity != "" {
+		if _, err := checks.ParseSeverity(ds.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ds DependencySettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ds.Severity != "" {
+		sev, _ := checks.ParseSeverity(ds.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type FormatSettings struct {
+	Severity string `hcl:"severity,optional"`
+}
+
+func (fs FormatSettings) validate() error {
+	if fs.Severity != "" {
+		if _, err := checks.ParseSeverity(fs.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs FormatSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if fs.Severity != "" {
+		sev, _ := checks.ParseSeverity(fs.Severity)
+		return sev
+	}
+	return fallback
+}
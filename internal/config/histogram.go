@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type HistogramSettings struct {
+	Severity string `hcl:"severity,optional"`
+}
+
+func (hs HistogramSettings) validate() error {
+	if hs.Severity != "" {
+		if _, err := checks.ParseSeverity(hs.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (hs HistogramSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if hs.Severity != "" {
+		sev, _ := checks.ParseSeverity(hs.Severity)
+		return sev
+	}
+	return fallback
+}
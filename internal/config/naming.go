@@ -0,0 +1,29 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type NamingSettings struct {
+	Metric     bool   `hcl:"metric,optional"`
+	Operations bool   `hcl:"operations,optional"`
+	Level      bool   `hcl:"level,optional"`
+	Severity   string `hcl:"severity,optional"`
+}
+
+func (ns NamingSettings) validate() error {
+	if ns.Severity != "" {
+		if _, err := checks.ParseSeverity(ns.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ns NamingSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ns.Severity != "" {
+		sev, _ := checks.ParseSeverity(ns.Severity)
+		return sev
+	}
+	return fallback
+}
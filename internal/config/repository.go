@@ -1,5 +1,9 @@
 package config
 
+import (
+	"fmt"
+)
+
 type BitBucket struct {
 	URI        string `hcl:"uri"`
 	Timeout    string `hcl:"timeout"`
@@ -14,6 +18,43 @@ func (bb BitBucket) validate() error {
 	return nil
 }
 
+type GitHub struct {
+	BaseURI string `hcl:"baseuri,optional"`
+	Timeout string `hcl:"timeout,optional"`
+	Owner   string `hcl:"owner"`
+	Repo    string `hcl:"repo"`
+}
+
+func (gh GitHub) validate() error {
+	if gh.Timeout != "" {
+		if _, err := parseDuration(gh.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type GitLab struct {
+	CodeQuality string `hcl:"codequality,optional"`
+	URI         string `hcl:"uri,optional"`
+	Timeout     string `hcl:"timeout,optional"`
+	Project     string `hcl:"project,optional"`
+}
+
+func (gl GitLab) validate() error {
+	if gl.Timeout != "" {
+		if _, err := parseDuration(gl.Timeout); err != nil {
+			return err
+		}
+	}
+	if gl.URI != "" && gl.Project == "" {
+		return fmt.Errorf("gitlab project must be set when uri is configured")
+	}
+	return nil
+}
+
 type Repository struct {
 	BitBucket *BitBucket `hcl:"bitbucket,block"`
+	GitHub    *GitHub    `hcl:"github,block"`
+	GitLab    *GitLab    `hcl:"gitlab,block"`
 }
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type RoutingSettings struct {
+	Severity string `hcl:"severity,optional"`
+}
+
+func (rs RoutingSettings) validate() error {
+	if rs.Severity != "" {
+		if _, err := checks.ParseSeverity(rs.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs RoutingSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if rs.Severity != "" {
+		sev, _ := checks.ParseSeverity(rs.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -5,7 +5,9 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/cloudflare/pint/internal/alertmanager"
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/graph"
 	"github.com/cloudflare/pint/internal/parser"
 	"github.com/rs/zerolog/log"
 )
@@ -69,11 +71,58 @@ func (ml MatchLabel) isMatching(rule parser.Rule) bool {
 	return false
 }
 
+// MatchKubernetes filters rules by metadata of the PrometheusRule object
+// they were defined in
+type MatchKubernetes struct {
+	Namespace string      `hcl:"namespace,optional"`
+	Name      string      `hcl:"name,optional"`
+	Label     *MatchLabel `hcl:"label,block"`
+}
+
+func (mk MatchKubernetes) validate() error {
+	if _, err := regexp.Compile(mk.Namespace); err != nil {
+		return err
+	}
+	if _, err := regexp.Compile(mk.Name); err != nil {
+		return err
+	}
+	if mk.Label != nil {
+		if err := mk.Label.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mk MatchKubernetes) isMatching(rule parser.Rule) bool {
+	if rule.Kubernetes == nil {
+		return false
+	}
+	if mk.Namespace != "" && !strictRegex(mk.Namespace).MatchString(rule.Kubernetes.Namespace) {
+		return false
+	}
+	if mk.Name != "" && !strictRegex(mk.Name).MatchString(rule.Kubernetes.Name) {
+		return false
+	}
+	if mk.Label != nil {
+		keyRe := strictRegex(mk.Label.Key)
+		valRe := strictRegex(mk.Label.Value)
+		for k, v := range rule.Kubernetes.Labels {
+			if keyRe.MatchString(k) && valRe.MatchString(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
 type Match struct {
-	Path       string      `hcl:"path,optional"`
-	Kind       string      `hcl:"kind,optional"`
-	Label      *MatchLabel `hcl:"label,block"`
-	Annotation *MatchLabel `hcl:"annotation,block"`
+	Path       string           `hcl:"path,optional"`
+	Kind       string           `hcl:"kind,optional"`
+	Label      *MatchLabel      `hcl:"label,block"`
+	Annotation *MatchLabel      `hcl:"annotation,block"`
+	Kubernetes *MatchKubernetes `hcl:"kubernetes,block"`
 }
 
 func (m Match) validate() error {
@@ -96,6 +145,12 @@ func (m Match) validate() error {
 		}
 	}
 
+	if m.Kubernetes != nil {
+		if err := m.Kubernetes.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -103,6 +158,9 @@ type Rule struct {
 	Match      *Match               `hcl:"match,block"`
 	Aggregate  []AggregateSettings  `hcl:"aggregate,block"`
 	Rate       *RateSettings        `hcl:"rate,block"`
+	Counter    *CounterSettings     `hcl:"counter,block"`
+	For        *ForSettings         `hcl:"for,block"`
+	Histogram  *HistogramSettings   `hcl:"histogram,block"`
 	Annotation []AnnotationSettings `hcl:"annotation,block"`
 	Label      []AnnotationSettings `hcl:"label,block"`
 	Series     *SeriesSettings      `hcl:"series,block"`
@@ -110,12 +168,58 @@ type Rule struct {
 	Alerts     *AlertsSettings      `hcl:"alerts,block"`
 	Value      *ValueSettings       `hcl:"value,block"`
 	Reject     []RejectSettings     `hcl:"reject,block"`
+	Format     *FormatSettings      `hcl:"format,block"`
+	Naming     *NamingSettings      `hcl:"naming,block"`
+	Routing    *RoutingSettings     `hcl:"routing,block"`
+	Duplicate  *DuplicateSettings   `hcl:"duplicate,block"`
+	Dependency *DependencySettings  `hcl:"dependency,block"`
 }
 
-func (rule Rule) resolveChecks(path string, r parser.Rule, enabledChecks, disabledChecks []string, proms []PrometheusConfig) []checks.RuleChecker {
+// resolveFileChecks returns checks that validate whole files, only the path
+// filter from the match block applies to those
+func (rule Rule) resolveFileChecks(path string, enabledChecks, disabledChecks []string) []checks.FileChecker {
+	enabled := []checks.FileChecker{}
+
+	if rule.Match != nil && rule.Match.Path != "" {
+		re := strictRegex(rule.Match.Path)
+		if !re.MatchString(path) {
+			return enabled
+		}
+	}
+
+	if rule.Format != nil && isEnabled(enabledChecks, disabledChecks, checks.FormatCheckName, parser.Rule{}) {
+		enabled = append(enabled, checks.NewFormatCheck(rule.Format.getSeverity(checks.Fatal)))
+	}
+
+	return enabled
+}
+
+// resolveRuleSetChecks returns checks that need to know about all other
+// scanned rules
+func (rule Rule) resolveRuleSetChecks(path string, r parser.Rule, g *graph.Graph, enabledChecks, disabledChecks []string) []checks.RuleChecker {
 	enabled := []checks.RuleChecker{}
 
-	if rule.Match != nil && rule.Match.Kind != "" {
+	if !rule.isMatching(path, r) {
+		return enabled
+	}
+
+	if rule.Duplicate != nil && isEnabled(enabledChecks, disabledChecks, checks.DuplicateCheckName, r) {
+		enabled = append(enabled, checks.NewDuplicateCheck(g, path, rule.Duplicate.getSeverity(checks.Bug)))
+	}
+
+	if rule.Dependency != nil && isEnabled(enabledChecks, disabledChecks, checks.DependencyCheckName, r) {
+		enabled = append(enabled, checks.NewDependencyCheck(g, rule.Dependency.getSeverity(checks.Warning)))
+	}
+
+	return enabled
+}
+
+func (rule Rule) isMatching(path string, r parser.Rule) bool {
+	if rule.Match == nil {
+		return true
+	}
+
+	if rule.Match.Kind != "" {
 		var isAllowed bool
 		recordingEnabled := rule.Match.Kind == recordingRuleType
 		alertingEnabled := rule.Match.Kind == alertingRuleType
@@ -126,25 +230,39 @@ func (rule Rule) resolveChecks(path string, r parser.Rule, enabledChecks, disabl
 			isAllowed = true
 		}
 		if !isAllowed {
-			return enabled
+			return false
 		}
 	}
 
-	if rule.Match != nil {
-		if rule.Match.Path != "" {
-			re := strictRegex(rule.Match.Path)
-			if !re.MatchString(path) {
-				return enabled
-			}
+	if rule.Match.Path != "" {
+		re := strictRegex(rule.Match.Path)
+		if !re.MatchString(path) {
+			return false
 		}
+	}
 
-		if rule.Match.Label != nil {
-			if !rule.Match.Label.isMatching(r) {
-				return enabled
-			}
+	if rule.Match.Label != nil {
+		if !rule.Match.Label.isMatching(r) {
+			return false
+		}
+	}
+
+	if rule.Match.Kubernetes != nil {
+		if !rule.Match.Kubernetes.isMatching(r) {
+			return false
 		}
 	}
 
+	return true
+}
+
+func (rule Rule) resolveChecks(path string, r parser.Rule, enabledChecks, disabledChecks []string, proms []PrometheusConfig, am *alertmanager.Config) []checks.RuleChecker {
+	enabled := []checks.RuleChecker{}
+
+	if !rule.isMatching(path, r) {
+		return enabled
+	}
+
 	if len(rule.Aggregate) > 0 {
 		var nameRegex *regexp.Regexp
 		for _, aggr := range rule.Aggregate {
@@ -178,6 +296,33 @@ func (rule Rule) resolveChecks(path string, r parser.Rule, enabledChecks, disabl
 		}
 	}
 
+	if rule.Histogram != nil && isEnabled(enabledChecks, disabledChecks, checks.HistogramCheckName, r) {
+		enabled = append(enabled, checks.NewHistogramSuffixCheck(rule.Histogram.getSeverity(checks.Warning)))
+	}
+
+	if rule.Counter != nil && isEnabled(enabledChecks, disabledChecks, checks.CounterCheckName, r) {
+		severity := rule.Counter.getSeverity(checks.Warning)
+		// without any Prometheus server metric types can still be guessed
+		// from metric names, servers with metadata would report the same
+		// problems, so the offline check is only needed when there are none
+		if len(proms) == 0 {
+			enabled = append(enabled, checks.NewCounterCheck("", "", 0, severity))
+		}
+		for _, prom := range proms {
+			timeout, _ := parseDuration(prom.Timeout)
+			enabled = append(enabled, checks.NewCounterCheck(prom.Name, prom.URI, timeout, severity))
+		}
+	}
+
+	if rule.For != nil && isEnabled(enabledChecks, disabledChecks, checks.ForCheckName, r) {
+		severity := rule.For.getSeverity(checks.Warning)
+		enabled = append(enabled, checks.NewForCheck("", "", 0, severity))
+		for _, prom := range proms {
+			timeout, _ := parseDuration(prom.Timeout)
+			enabled = append(enabled, checks.NewForCheck(prom.Name, prom.URI, timeout, severity))
+		}
+	}
+
 	if rule.Cost != nil && isEnabled(enabledChecks, disabledChecks, checks.CostCheckName, r) {
 		severity := rule.Cost.getSeverity(checks.Bug)
 		for _, prom := range proms {
@@ -228,9 +373,10 @@ func (rule Rule) resolveChecks(path string, r parser.Rule, enabledChecks, disabl
 		if rule.Alerts.Resolve != "" {
 			qResolve, _ = parseDuration(rule.Alerts.Resolve)
 		}
+		severity := rule.Alerts.getSeverity(checks.Warning)
 		for _, prom := range proms {
 			timeout, _ := parseDuration(prom.Timeout)
-			enabled = append(enabled, checks.NewAlertsCheck(prom.Name, prom.URI, timeout, qRange, qStep, qResolve))
+			enabled = append(enabled, checks.NewAlertsCheck(prom.Name, prom.URI, timeout, qRange, qStep, qResolve, rule.Alerts.Labels, rule.Alerts.MaxAlerts, rule.Alerts.MaxFlapping, rule.Alerts.DefaultRoute, severity, am))
 		}
 	}
 
@@ -239,6 +385,15 @@ func (rule Rule) resolveChecks(path string, r parser.Rule, enabledChecks, disabl
 		enabled = append(enabled, checks.NewValueCheck(severity))
 	}
 
+	if rule.Routing != nil && am != nil && isEnabled(enabledChecks, disabledChecks, checks.RoutingCheckName, r) {
+		enabled = append(enabled, checks.NewRoutingCheck(am, rule.Routing.getSeverity(checks.Warning)))
+	}
+
+	if rule.Naming != nil && isEnabled(enabledChecks, disabledChecks, checks.NamingCheckName, r) {
+		severity := rule.Naming.getSeverity(checks.Warning)
+		enabled = append(enabled, checks.NewNamingCheck(rule.Naming.Metric, rule.Naming.Operations, rule.Naming.Level, severity))
+	}
+
 	if len(rule.Reject) > 0 && isEnabled(enabledChecks, disabledChecks, checks.RejectCheckName, r) {
 		for _, reject := range rule.Reject {
 			severity := reject.getSeverity(checks.Bug)
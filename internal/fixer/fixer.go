@@ -0,0 +1,272 @@
+package fixer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+// Result of applying fixes to a single file
+type Result struct {
+	Content []byte
+	Applied int
+	Skipped int
+}
+
+type change struct {
+	start int
+	end   int
+	text  string
+	claim bool
+	seq   int
+}
+
+func (c change) overlaps(o change) bool {
+	if c.start == c.end || o.start == o.end {
+		return c.start == o.start && c.end == o.end
+	}
+	return c.start < o.end && o.start < c.end
+}
+
+// Apply will modify content using all fixes that can be applied and don't
+// conflict with any other fix applied before them.
+// Fixes are applied in the order they were passed, a fix that conflicts
+// with any previous fix, or that doesn't match the content, is skipped.
+func Apply(content []byte, fixes []checks.Fix) (result Result) {
+	orig := string(content)
+	if !strings.HasSuffix(orig, "\n") {
+		orig += "\n"
+	}
+	lines := splitLines(orig)
+
+	accepted := []change{}
+	for _, fix := range fixes {
+		changes, err := resolve(lines, fix, len(accepted))
+		if err == nil {
+			for _, c := range changes {
+				if !c.claim {
+					continue
+				}
+				for _, a := range accepted {
+					if a.claim && c.overlaps(a) {
+						err = fmt.Errorf("conflicts with another fix")
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+		accepted = append(accepted, changes...)
+		result.Applied++
+	}
+
+	if result.Applied == 0 {
+		result.Content = content
+		return result
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		if accepted[i].start != accepted[j].start {
+			return accepted[i].start < accepted[j].start
+		}
+		// insertions must be placed before any text that follows them
+		ie := accepted[i].start == accepted[i].end
+		je := accepted[j].start == accepted[j].end
+		if ie != je {
+			return ie
+		}
+		return accepted[i].seq < accepted[j].seq
+	})
+
+	var out strings.Builder
+	var cursor int
+	for _, c := range accepted {
+		if c.start > cursor {
+			out.WriteString(orig[cursor:c.start])
+		}
+		out.WriteString(c.text)
+		if c.end > cursor {
+			cursor = c.end
+		}
+	}
+	out.WriteString(orig[cursor:])
+	result.Content = []byte(out.String())
+	// new line added to orig must not be left in files that didn't end with it
+	if !strings.HasSuffix(string(content), "\n") {
+		result.Content = []byte(strings.TrimSuffix(out.String(), "\n"))
+	}
+
+	return result
+}
+
+type line struct {
+	start int
+	text  string // line content including the trailing new line
+}
+
+func splitLines(content string) (lines []line) {
+	var offset int
+	for offset < len(content) {
+		end := strings.IndexByte(content[offset:], '\n')
+		if end < 0 {
+			end = len(content)
+		} else {
+			end += offset + 1
+		}
+		lines = append(lines, line{start: offset, text: content[offset:end]})
+		offset = end
+	}
+	return lines
+}
+
+func resolve(lines []line, fix checks.Fix, seq int) (changes []change, err error) {
+	for _, edit := range fix.Edits {
+		var c change
+		switch edit.Op {
+		case checks.ReplaceText:
+			c, err = resolveReplace(lines, edit)
+		case checks.DeleteLines:
+			c, err = resolveDelete(lines, edit)
+		case checks.InsertLines:
+			c, err = resolveInsert(lines, edit)
+		case checks.CopyLines:
+			c, err = resolveCopy(lines, edit)
+		default:
+			err = fmt.Errorf("unknown edit operation: %d", edit.Op)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.seq = seq + len(changes)
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func checkRange(lines []line, first, last int) error {
+	if first < 1 || last < first || last > len(lines) {
+		return fmt.Errorf("invalid line range %d-%d", first, last)
+	}
+	return nil
+}
+
+func resolveReplace(lines []line, edit checks.Edit) (change, error) {
+	if err := checkRange(lines, edit.FirstLine, edit.LastLine); err != nil {
+		return change{}, err
+	}
+	if edit.Old == "" {
+		return change{}, fmt.Errorf("nothing to replace")
+	}
+
+	offset := lines[edit.FirstLine-1].start
+	text := joinLines(lines, edit.FirstLine, edit.LastLine)
+	var pos int
+	for i := 0; i <= edit.Match; i++ {
+		idx := strings.Index(text[pos:], edit.Old)
+		if idx < 0 {
+			return change{}, fmt.Errorf("%q not found", edit.Old)
+		}
+		pos += idx
+		if i < edit.Match {
+			pos += len(edit.Old)
+		}
+	}
+	return change{
+		start: offset + pos,
+		end:   offset + pos + len(edit.Old),
+		text:  edit.New,
+		claim: true,
+	}, nil
+}
+
+func resolveDelete(lines []line, edit checks.Edit) (change, error) {
+	if err := checkRange(lines, edit.FirstLine, edit.LastLine); err != nil {
+		return change{}, err
+	}
+	last := lines[edit.LastLine-1]
+	return change{
+		start: lines[edit.FirstLine-1].start,
+		end:   last.start + len(last.text),
+		claim: true,
+	}, nil
+}
+
+func resolveInsert(lines []line, edit checks.Edit) (change, error) {
+	if err := checkRange(lines, edit.Target, edit.Target); err != nil {
+		return change{}, err
+	}
+	if err := checkRange(lines, edit.IndentFrom, edit.IndentFrom); err != nil {
+		return change{}, err
+	}
+	indent := indentation(lines[edit.IndentFrom-1].text)
+	var text strings.Builder
+	for _, l := range strings.Split(edit.New, "\n") {
+		text.WriteString(indent)
+		text.WriteString(l)
+		text.WriteString("\n")
+	}
+	pos := insertPosition(lines, edit.Target)
+	return change{start: pos, end: pos, text: text.String(), claim: true}, nil
+}
+
+func resolveCopy(lines []line, edit checks.Edit) (change, error) {
+	if err := checkRange(lines, edit.FirstLine, edit.LastLine); err != nil {
+		return change{}, err
+	}
+	if err := checkRange(lines, edit.Target, edit.Target); err != nil {
+		return change{}, err
+	}
+
+	var strip int
+	var add string
+	if edit.IndentFrom != 0 {
+		if err := checkRange(lines, edit.IndentFrom, edit.IndentFrom); err != nil {
+			return change{}, err
+		}
+		have := indentation(lines[edit.FirstLine-1].text)
+		want := indentation(lines[edit.IndentFrom-1].text)
+		if len(want) > len(have) {
+			add = want[len(have):]
+		} else {
+			strip = len(have) - len(want)
+		}
+	}
+
+	var text strings.Builder
+	for i := edit.FirstLine; i <= edit.LastLine; i++ {
+		l := lines[i-1].text
+		if s := len(indentation(l)); s < strip {
+			l = l[s:]
+		} else {
+			l = l[strip:]
+		}
+		if strings.TrimSpace(l) != "" {
+			text.WriteString(add)
+		}
+		text.WriteString(l)
+	}
+	pos := insertPosition(lines, edit.Target)
+	return change{start: pos, end: pos, text: text.String()}, nil
+}
+
+func joinLines(lines []line, first, last int) string {
+	var sb strings.Builder
+	for i := first; i <= last; i++ {
+		sb.WriteString(lines[i-1].text)
+	}
+	return sb.String()
+}
+
+func insertPosition(lines []line, target int) int {
+	l := lines[target-1]
+	return l.start + len(l.text)
+}
+
+func indentation(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
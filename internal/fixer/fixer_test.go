@@ -0,0 +1,154 @@
+package fixer_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/fixer"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApply(t *testing.T) {
+	type testCaseT struct {
+		description string
+		content     string
+		fixes       []checks.Fix
+		output      string
+		applied     int
+		skipped     int
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no fixes",
+			content:     "- record: foo\n  expr: sum(foo) by(job)",
+			output:      "- record: foo\n  expr: sum(foo) by(job)",
+		},
+		{
+			description: "replace text",
+			content:     "# comment\n- record: foo\n  expr: sum(foo) by(job) # keep me\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 3, LastLine: 3, Old: "by(job)", New: "by (job, instance)"}}},
+			},
+			output:  "# comment\n- record: foo\n  expr: sum(foo) by (job, instance) # keep me\n",
+			applied: 1,
+		},
+		{
+			description: "missing trailing new line is preserved",
+			content:     "- record: foo\n  expr: sum(foo) by(job)",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 2, LastLine: 2, Old: " by(job)", New: ""}}},
+			},
+			output:  "- record: foo\n  expr: sum(foo)",
+			applied: 1,
+		},
+		{
+			description: "delete line from block scalar",
+			content:     "- record: foo\n  expr: |\n    sum(foo)\n    by(job)\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Op: checks.DeleteLines, FirstLine: 4, LastLine: 4}}},
+			},
+			output:  "- record: foo\n  expr: |\n    sum(foo)\n",
+			applied: 1,
+		},
+		{
+			description: "replace second match",
+			content:     "- record: foo\n  expr: |\n    sum(foo) by(job)\n    /\n    sum(bar) by(job)\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 2, LastLine: 5, Old: "by(job)", New: "by(instance)", Match: 1}}},
+			},
+			output:  "- record: foo\n  expr: |\n    sum(foo) by(job)\n    /\n    sum(bar) by(instance)\n",
+			applied: 1,
+		},
+		{
+			description: "missing text is skipped",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 2, LastLine: 2, Old: "by(job)", New: ""}}},
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 5, LastLine: 5, Old: "foo", New: "bar"}}},
+			},
+			output:  "- record: foo\n  expr: sum(foo)\n",
+			skipped: 2,
+		},
+		{
+			description: "conflicting fixes",
+			content:     "- record: foo\n  expr: sum(rate(foo[1m])) without(job)\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 2, LastLine: 2, Old: "without(job)", New: "without (job, instance)"}}},
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 2, LastLine: 2, Old: "without(job)", New: "without ()"}}},
+				{Edits: []checks.Edit{{Op: checks.ReplaceText, FirstLine: 2, LastLine: 2, Old: "[1m]", New: "[2m]"}}},
+			},
+			output:  "- record: foo\n  expr: sum(rate(foo[2m])) without (job, instance)\n",
+			applied: 2,
+			skipped: 1,
+		},
+		{
+			description: "move label to new annotations",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: critical\n    value: '{{ $value }}'\n- alert: bar\n  expr: up == 0\n",
+			fixes: []checks.Fix{
+				{
+					Edits: []checks.Edit{
+						{Op: checks.InsertLines, New: "annotations:", Target: 5, IndentFrom: 3},
+						{Op: checks.CopyLines, FirstLine: 5, LastLine: 5, Target: 5},
+						{Op: checks.DeleteLines, FirstLine: 5, LastLine: 5},
+					},
+				},
+			},
+			output:  "- alert: foo\n  expr: up == 0\n  labels:\n    severity: critical\n  annotations:\n    value: '{{ $value }}'\n- alert: bar\n  expr: up == 0\n",
+			applied: 1,
+		},
+		{
+			description: "move label to existing annotations",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    value: |\n      {{ $value }}\n    severity: critical\n  annotations:\n      summary: foo\n",
+			fixes: []checks.Fix{
+				{
+					Edits: []checks.Edit{
+						{Op: checks.CopyLines, FirstLine: 4, LastLine: 5, Target: 7, IndentFrom: 8},
+						{Op: checks.DeleteLines, FirstLine: 4, LastLine: 5},
+					},
+				},
+			},
+			output:  "- alert: foo\n  expr: up == 0\n  labels:\n    severity: critical\n  annotations:\n      value: |\n        {{ $value }}\n      summary: foo\n",
+			applied: 1,
+		},
+		{
+			description: "inserting annotations twice conflicts",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    a: '{{ $value }}'\n    b: '{{ $value }}'\n",
+			fixes: []checks.Fix{
+				{
+					Edits: []checks.Edit{
+						{Op: checks.InsertLines, New: "annotations:", Target: 5, IndentFrom: 3},
+						{Op: checks.CopyLines, FirstLine: 4, LastLine: 4, Target: 5},
+						{Op: checks.DeleteLines, FirstLine: 4, LastLine: 4},
+					},
+				},
+				{
+					Edits: []checks.Edit{
+						{Op: checks.InsertLines, New: "annotations:", Target: 5, IndentFrom: 3},
+						{Op: checks.CopyLines, FirstLine: 5, LastLine: 5, Target: 5},
+						{Op: checks.DeleteLines, FirstLine: 5, LastLine: 5},
+					},
+				},
+			},
+			output:  "- alert: foo\n  expr: up == 0\n  labels:\n    b: '{{ $value }}'\n  annotations:\n    a: '{{ $value }}'\n",
+			applied: 1,
+			skipped: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result := fixer.Apply([]byte(tc.content), tc.fixes)
+			if diff := cmp.Diff(tc.output, string(result.Content)); diff != "" {
+				t.Errorf("Apply() returned wrong content (-want +got):\n%s", diff)
+			}
+			if result.Applied != tc.applied {
+				t.Errorf("Apply() applied %d fix(es), expected %d", result.Applied, tc.applied)
+			}
+			if result.Skipped != tc.skipped {
+				t.Errorf("Apply() skipped %d fix(es), expected %d", result.Skipped, tc.skipped)
+			}
+		})
+	}
+}
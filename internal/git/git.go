@@ -3,6 +3,7 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -126,3 +127,68 @@ func CommitRange(cmd CommandRunner, baseBranch string) (cr CommitRangeResults, e
 
 	return
 }
+
+// MergeBase returns the best common ancestor of HEAD and given branch
+func MergeBase(cmd CommandRunner, baseBranch string) (string, error) {
+	commit, err := cmd("merge-base", baseBranch, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(string(commit), "\n"), nil
+}
+
+// GrepFiles returns paths of all files present in the repository at given
+// commit that contain any of given words
+func GrepFiles(cmd CommandRunner, commit string, words []string) (paths []string, err error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+	args := []string{"grep", "-l", "-F", "-w"}
+	for _, word := range words {
+		args = append(args, "-e", word)
+	}
+	args = append(args, commit, "--")
+	out, err := cmd(args...)
+	if err != nil {
+		// git grep exits with 1 when nothing was found
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			paths = append(paths, strings.TrimPrefix(line, commit+":"))
+		}
+	}
+	return paths, nil
+}
+
+// ChangedFiles returns paths of all files modified between given commit and
+// HEAD, before contains paths present at given commit and after contains
+// paths present at HEAD, renamed files are returned as removed and added
+func ChangedFiles(cmd CommandRunner, commit string) (before, after []string, err error) {
+	out, err := cmd("diff", "--name-status", "--no-renames", commit, "HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if parts[0] != "A" {
+			before = append(before, parts[1])
+		}
+		if parts[0] != "D" {
+			after = append(after, parts[1])
+		}
+	}
+	return before, after, nil
+}
+
+// FileContent returns the content of a file at given commit
+func FileContent(cmd CommandRunner, commit, path string) ([]byte, error) {
+	return cmd("show", fmt.Sprintf("%s:%s", commit, path))
+}
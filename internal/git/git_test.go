@@ -2,6 +2,7 @@ package git_test
 
 import (
 	"fmt"
+	"os/exec"
 	"strconv"
 	"testing"
 
@@ -171,3 +172,75 @@ func TestCommitRange(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeBase(t *testing.T) {
+	mock := func(args ...string) ([]byte, error) {
+		if diff := cmp.Diff([]string{"merge-base", "main", "HEAD"}, args); diff != "" {
+			return nil, fmt.Errorf("unexpected args: %s", diff)
+		}
+		return []byte("commit1\n"), nil
+	}
+
+	commit, err := git.MergeBase(mock, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit != "commit1" {
+		t.Errorf("git.MergeBase() returned %q, expected commit1", commit)
+	}
+
+	_, err = git.MergeBase(func(args ...string) ([]byte, error) {
+		return nil, fmt.Errorf("mock error")
+	}, "main")
+	if err == nil {
+		t.Errorf("git.MergeBase() didn't return any error")
+	}
+}
+
+func TestGrepFiles(t *testing.T) {
+	mock := func(args ...string) ([]byte, error) {
+		if diff := cmp.Diff([]string{"grep", "-l", "-F", "-w", "-e", "foo", "-e", "bar", "commit1", "--"}, args); diff != "" {
+			return nil, fmt.Errorf("unexpected args: %s", diff)
+		}
+		return []byte("commit1:foo.yml\ncommit1:rules/bar.yml\n"), nil
+	}
+
+	paths, err := git.GrepFiles(mock, "commit1", []string{"foo", "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"foo.yml", "rules/bar.yml"}, paths); diff != "" {
+		t.Errorf("git.GrepFiles() returned wrong output (-want +got):\n%s", diff)
+	}
+
+	nomatch := func(args ...string) ([]byte, error) {
+		return nil, exec.Command("false").Run()
+	}
+	paths, err = git.GrepFiles(nomatch, "commit1", []string{"foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("git.GrepFiles() returned paths when nothing matched: %v", paths)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	mock := func(args ...string) ([]byte, error) {
+		if diff := cmp.Diff([]string{"diff", "--name-status", "--no-renames", "commit1", "HEAD"}, args); diff != "" {
+			return nil, fmt.Errorf("unexpected args: %s", diff)
+		}
+		return []byte("M\tfoo.yml\nA\trules/new.yml\nD\trules/old.yml\n"), nil
+	}
+
+	before, after, err := git.ChangedFiles(mock, "commit1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"foo.yml", "rules/old.yml"}, before); diff != "" {
+		t.Errorf("git.ChangedFiles() returned wrong before paths (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"foo.yml", "rules/new.yml"}, after); diff != "" {
+		t.Errorf("git.ChangedFiles() returned wrong after paths (-want +got):\n%s", diff)
+	}
+}
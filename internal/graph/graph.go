@@ -0,0 +1,283 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	KindRecording = "recording"
+	KindAlerting  = "alerting"
+	// KindMissing is used for metrics that look like recording rules
+	// but are not defined in any of the files used to build the graph
+	KindMissing = "missing"
+)
+
+// Entry is a single rule used to build the graph
+type Entry struct {
+	Path string
+	Rule parser.Rule
+}
+
+// Source is the location of a rule definition
+type Source struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+// Node is either a recording rule, an alerting rule or a missing recording
+// rule, recording rules with the same name share a single node
+type Node struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Sources []Source `json:"sources,omitempty"`
+
+	entries []Entry
+}
+
+// Edge links a rule with a recording rule it's using in its query
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+
+	nodes map[string]*Node
+	deps  map[string][]string
+}
+
+// New builds a dependency graph from all given rules
+func New(entries []Entry) *Graph {
+	g := Graph{
+		Nodes: []*Node{},
+		Edges: []Edge{},
+		nodes: map[string]*Node{},
+		deps:  map[string][]string{},
+	}
+
+	for _, entry := range entries {
+		if entry.Rule.RecordingRule == nil {
+			continue
+		}
+		g.addNode(entry.Rule.Name(), KindRecording, entry)
+	}
+
+	for _, entry := range entries {
+		if entry.Rule.Error.Err != nil {
+			continue
+		}
+		var from string
+		if entry.Rule.AlertingRule != nil {
+			from = g.addNode(entry.Rule.Name(), KindAlerting, entry)
+		} else {
+			from = entry.Rule.Name()
+		}
+		for _, name := range g.Dependencies(entry.Rule) {
+			to := nodeID(name, KindRecording)
+			if _, ok := g.nodes[to]; !ok {
+				to = g.addNode(name, KindMissing, Entry{})
+			}
+			g.addEdge(from, to)
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From == g.Edges[j].From {
+			return g.Edges[i].To < g.Edges[j].To
+		}
+		return g.Edges[i].From < g.Edges[j].From
+	})
+
+	return &g
+}
+
+func nodeID(name, kind string) string {
+	if kind == KindAlerting {
+		return "alert/" + name
+	}
+	return name
+}
+
+func (g *Graph) addNode(name, kind string, entry Entry) string {
+	id := nodeID(name, kind)
+	node, ok := g.nodes[id]
+	if !ok {
+		node = &Node{ID: id, Name: name, Kind: kind}
+		g.nodes[id] = node
+		g.Nodes = append(g.Nodes, node)
+	}
+	if entry.Path != "" {
+		node.entries = append(node.entries, entry)
+		node.Sources = append(node.Sources, Source{
+			Path: entry.Path,
+			Line: firstLine(entry.Rule.Lines()),
+		})
+	}
+	return id
+}
+
+func (g *Graph) addEdge(from, to string) {
+	// recording rules using a metric with the same name are usually
+	// aggregating raw metrics and filter out own results using labels
+	if from == to {
+		return
+	}
+	for _, dep := range g.deps[from] {
+		if dep == to {
+			return
+		}
+	}
+	g.deps[from] = append(g.deps[from], to)
+	g.Edges = append(g.Edges, Edge{From: from, To: to})
+}
+
+func firstLine(lines []int) (line int) {
+	for _, l := range lines {
+		if line == 0 || l < line {
+			line = l
+		}
+	}
+	return line
+}
+
+// Dependencies returns names of all recording rules used in the query of
+// given rule, this includes metrics that look like recording rules but are
+// not defined
+func (g *Graph) Dependencies(rule parser.Rule) (names []string) {
+	for _, name := range MetricNames(rule) {
+		if _, ok := g.nodes[nodeID(name, KindRecording)]; ok || strings.Contains(name, ":") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Definitions returns all rules with the same kind and name as given rule,
+// including the rule itself
+func (g *Graph) Definitions(rule parser.Rule) []Entry {
+	var id string
+	switch {
+	case rule.RecordingRule != nil:
+		id = nodeID(rule.Name(), KindRecording)
+	case rule.AlertingRule != nil:
+		id = nodeID(rule.Name(), KindAlerting)
+	default:
+		return nil
+	}
+	if node, ok := g.nodes[id]; ok {
+		return node.entries
+	}
+	return nil
+}
+
+// MetricNames returns names of all metrics used in the query of given rule
+func MetricNames(rule parser.Rule) (names []string) {
+	if rule.Error.Err != nil || rule.Expr().SyntaxError != nil {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	for _, name := range selectorNames(rule.Expr().Query) {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// Missing returns names of all metrics used in the query of given rule that
+// look like recording rules but are not defined
+func (g *Graph) Missing(rule parser.Rule) (names []string) {
+	for _, name := range g.Dependencies(rule) {
+		if node, ok := g.nodes[nodeID(name, KindRecording)]; ok && node.Kind == KindMissing {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Cycle returns the list of recording rules that form a dependency cycle
+// starting and ending with given recording rule, or nil if there's no cycle
+func (g *Graph) Cycle(name string) []string {
+	return g.findPath(name, name, map[string]struct{}{})
+}
+
+func (g *Graph) findPath(from, to string, visited map[string]struct{}) []string {
+	visited[from] = struct{}{}
+	for _, dep := range g.deps[from] {
+		if dep == to {
+			return []string{from, to}
+		}
+		if _, ok := visited[dep]; ok {
+			continue
+		}
+		if path := g.findPath(dep, to, visited); path != nil {
+			return append([]string{from}, path...)
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the graph as a JSON document
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph using Graphviz DOT language
+func (g *Graph) WriteDOT(w io.Writer) error {
+	lines := []string{"digraph pint {"}
+	for _, node := range g.Nodes {
+		var attrs string
+		switch node.Kind {
+		case KindRecording:
+			attrs = "shape=box"
+		case KindAlerting:
+			attrs = "shape=ellipse"
+		case KindMissing:
+			attrs = "shape=box, style=dashed, color=red"
+		}
+		lines = append(lines, fmt.Sprintf("  %q [label=%q, %s];", node.ID, node.Name, attrs))
+	}
+	for _, edge := range g.Edges {
+		lines = append(lines, fmt.Sprintf("  %q -> %q;", edge.From, edge.To))
+	}
+	lines = append(lines, "}")
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func selectorNames(node *parser.PromQLNode) (names []string) {
+	if vs, ok := node.Node.(*promParser.VectorSelector); ok {
+		if vs.Name != "" {
+			names = append(names, vs.Name)
+		} else {
+			for _, m := range vs.LabelMatchers {
+				if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+					names = append(names, m.Value)
+				}
+			}
+		}
+	}
+	for _, child := range node.Children {
+		names = append(names, selectorNames(child)...)
+	}
+	return names
+}
@@ -0,0 +1,171 @@
+package graph_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/graph"
+	"github.com/cloudflare/pint/internal/parser"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func parseEntries(t *testing.T, path, content string) (entries []graph.Entry) {
+	p := parser.NewParser()
+	rules, err := p.Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range rules {
+		entries = append(entries, graph.Entry{Path: path, Rule: rule})
+	}
+	return entries
+}
+
+func TestGraph(t *testing.T) {
+	entries := parseEntries(t, "a.yml", `- record: job:up:sum
+  expr: sum(up) by(job)
+- record: job:up:sum
+  expr: sum(up{cluster="b"}) by(job)
+`)
+	entries = append(entries, parseEntries(t, "b.yml", `- alert: Job Is Down
+  expr: job:up:sum == 0 or {__name__="job:up:count"} == 0
+- alert: Instance Is Down
+  expr: up == 0
+- record: foo
+  expr: sum(job:up:sum)
+- record: bar
+  expr: foo
+`)...)
+
+	g := graph.New(entries)
+
+	nodes := []*graph.Node{
+		{ID: "alert/Instance Is Down", Name: "Instance Is Down", Kind: graph.KindAlerting, Sources: []graph.Source{{Path: "b.yml", Line: 3}}},
+		{ID: "alert/Job Is Down", Name: "Job Is Down", Kind: graph.KindAlerting, Sources: []graph.Source{{Path: "b.yml", Line: 1}}},
+		{ID: "bar", Name: "bar", Kind: graph.KindRecording, Sources: []graph.Source{{Path: "b.yml", Line: 7}}},
+		{ID: "foo", Name: "foo", Kind: graph.KindRecording, Sources: []graph.Source{{Path: "b.yml", Line: 5}}},
+		{ID: "job:up:count", Name: "job:up:count", Kind: graph.KindMissing},
+		{ID: "job:up:sum", Name: "job:up:sum", Kind: graph.KindRecording, Sources: []graph.Source{{Path: "a.yml", Line: 1}, {Path: "a.yml", Line: 3}}},
+	}
+	if diff := cmp.Diff(nodes, g.Nodes, cmpopts.IgnoreUnexported(graph.Node{})); diff != "" {
+		t.Errorf("New() returned wrong nodes (-want +got):\n%s", diff)
+	}
+
+	edges := []graph.Edge{
+		{From: "alert/Job Is Down", To: "job:up:count"},
+		{From: "alert/Job Is Down", To: "job:up:sum"},
+		{From: "bar", To: "foo"},
+		{From: "foo", To: "job:up:sum"},
+	}
+	if diff := cmp.Diff(edges, g.Edges); diff != "" {
+		t.Errorf("New() returned wrong edges (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"job:up:count"}, g.Missing(entries[2].Rule)); diff != "" {
+		t.Errorf("Missing() returned wrong names (-want +got):\n%s", diff)
+	}
+
+	if cycle := g.Cycle("bar"); cycle != nil {
+		t.Errorf("Cycle() returned %v, expected nil", cycle)
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := `digraph pint {
+  "alert/Instance Is Down" [label="Instance Is Down", shape=ellipse];
+  "alert/Job Is Down" [label="Job Is Down", shape=ellipse];
+  "bar" [label="bar", shape=box];
+  "foo" [label="foo", shape=box];
+  "job:up:count" [label="job:up:count", shape=box, style=dashed, color=red];
+  "job:up:sum" [label="job:up:sum", shape=box];
+  "alert/Job Is Down" -> "job:up:count";
+  "alert/Job Is Down" -> "job:up:sum";
+  "bar" -> "foo";
+  "foo" -> "job:up:sum";
+}
+`
+	if diff := cmp.Diff(dot, buf.String()); diff != "" {
+		t.Errorf("WriteDOT() returned wrong output (-want +got):\n%s", diff)
+	}
+}
+
+func TestGraphCycle(t *testing.T) {
+	g := graph.New(parseEntries(t, "a.yml", `- record: a
+  expr: b
+- record: b
+  expr: c + a
+- record: c
+  expr: up
+`))
+
+	if diff := cmp.Diff([]string{"a", "b", "a"}, g.Cycle("a")); diff != "" {
+		t.Errorf("Cycle() returned wrong path (-want +got):\n%s", diff)
+	}
+	if cycle := g.Cycle("c"); cycle != nil {
+		t.Errorf("Cycle() returned %v, expected nil", cycle)
+	}
+}
+
+func TestGraphSelfReference(t *testing.T) {
+	g := graph.New(parseEntries(t, "a.yml", `- record: foo
+  expr: sum(foo{aggregated!="true"})
+  labels:
+    aggregated: "true"
+`))
+
+	if len(g.Edges) != 0 {
+		t.Errorf("New() returned edges %v, expected none", g.Edges)
+	}
+	if cycle := g.Cycle("foo"); cycle != nil {
+		t.Errorf("Cycle() returned %v, expected nil", cycle)
+	}
+}
+
+func TestRemoved(t *testing.T) {
+	before := parseEntries(t, "a.yml", `- record: job:up:sum
+  expr: sum(up) by(job)
+- record: job:up:count
+  expr: count(up) by(job)
+- record: job:up:count
+  expr: count(up{cluster="a"}) by(job)
+- record: job:up:min
+  expr: min(up) by(job)
+- alert: Job Is Down
+  expr: job:up:sum == 0
+- alert: Instance Is Down
+  expr: up == 0
+`)
+	after := parseEntries(t, "a.yml", `- record: job:up:sum
+  expr: sum(up) by(job)
+- record: job:up:minimum
+  expr: min(up)   by(job)
+- alert: Instance Down
+  expr: up == 0
+- alert: Job Down
+  expr: job:up:sum == 1
+`)
+
+	type removedRule struct {
+		Name      string
+		Line      int
+		RenamedTo string
+	}
+	var got []removedRule
+	for _, rr := range graph.Removed(before, after) {
+		got = append(got, removedRule{Name: rr.Rule.Name(), Line: rr.Rule.Lines()[0], RenamedTo: rr.RenamedTo})
+	}
+
+	expected := []removedRule{
+		{Name: "job:up:count", Line: 3},
+		{Name: "job:up:min", Line: 7, RenamedTo: "job:up:minimum"},
+		{Name: "Job Is Down", Line: 9},
+		{Name: "Instance Is Down", Line: 11, RenamedTo: "Instance Down"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Removed() returned wrong rules (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// RemovedRule is a rule present in the old rule set but not in the new one
+type RemovedRule struct {
+	Entry
+	// RenamedTo is the name of a rule of the same kind that uses the same
+	// query and is only present in the new rule set
+	RenamedTo string
+}
+
+// Removed compares two rule sets and returns all recording and alerting
+// rules with names that are only present in the old rule set, every name is
+// returned only once
+func Removed(before, after []Entry) (removed []RemovedRule) {
+	oldNames := ruleNames(before)
+	newNames := ruleNames(after)
+
+	seen := map[string]struct{}{}
+	for _, entry := range before {
+		id, ok := entryID(entry.Rule)
+		if !ok {
+			continue
+		}
+		if _, ok := newNames[id]; ok {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		rr := RemovedRule{Entry: entry}
+		for _, other := range after {
+			otherID, ok := entryID(other.Rule)
+			if !ok || other.Rule.Error.Err != nil {
+				continue
+			}
+			if _, ok := oldNames[otherID]; ok {
+				continue
+			}
+			if (entry.Rule.RecordingRule == nil) != (other.Rule.RecordingRule == nil) {
+				continue
+			}
+			if entry.Rule.Expr().Normalized() == other.Rule.Expr().Normalized() {
+				rr.RenamedTo = other.Rule.Name()
+				break
+			}
+		}
+		removed = append(removed, rr)
+	}
+
+	return removed
+}
+
+func entryID(rule parser.Rule) (string, bool) {
+	switch {
+	case rule.RecordingRule != nil:
+		return nodeID(rule.Name(), KindRecording), true
+	case rule.AlertingRule != nil:
+		return nodeID(rule.Name(), KindAlerting), true
+	}
+	return "", false
+}
+
+func ruleNames(entries []Entry) map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, entry := range entries {
+		if id, ok := entryID(entry.Rule); ok {
+			names[id] = struct{}{}
+		}
+	}
+	return names
+}
@@ -0,0 +1,364 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/cloudflare/pint/internal/checks"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LintFunc runs all checks on given file content and returns found problems
+type LintFunc func(path string, content []byte) []checks.Problem
+
+// RefreshFunc is called when files on disk might have changed, after the
+// client saved a document or notified the server about changed files
+type RefreshFunc func()
+
+type document struct {
+	uri      string
+	version  int
+	content  []byte
+	problems []checks.Problem
+	timer    *time.Timer
+}
+
+// Server is a Language Server Protocol server publishing problems found by
+// pint as diagnostics for all open documents
+type Server struct {
+	lint     LintFunc
+	refresh  RefreshFunc
+	debounce time.Duration
+
+	writeLock sync.Mutex
+	out       io.Writer
+
+	lock     sync.Mutex
+	docs     map[string]*document
+	shutdown bool
+}
+
+// NewServer creates a new server, if debounce is non-zero then checks will
+// run in the background once there were no changes to the document for
+// that long, otherwise checks run on every change before processing
+// next message, refresh can be nil
+func NewServer(lint LintFunc, refresh RefreshFunc, debounce time.Duration) *Server {
+	return &Server{
+		lint:     lint,
+		refresh:  refresh,
+		debounce: debounce,
+		docs:     map[string]*document{},
+	}
+}
+
+// Serve processes all messages read from in until the client sends the exit
+// notification or in is closed
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	defer s.stopTimers()
+
+	r := bufio.NewReader(in)
+	for {
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		var req request
+		if err = json.Unmarshal(body, &req); err != nil {
+			log.Error().Err(err).Msg("Failed to decode message")
+			if err = s.reply(nil, nil, &responseError{Code: parseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			s.lock.Lock()
+			shutdown := s.shutdown
+			s.lock.Unlock()
+			if !shutdown {
+				return fmt.Errorf("exit requested without shutdown")
+			}
+			return nil
+		}
+
+		result, rerr := s.handle(req)
+		if req.isNotification() {
+			if rerr != nil {
+				log.Error().Str("method", req.Method).Str("error", rerr.Message).Msg("Failed to handle notification")
+			}
+			continue
+		}
+		if err = s.reply(req.ID, result, rerr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) (interface{}, *responseError) {
+	log.Debug().Str("method", req.Method).Msg("Received message")
+
+	switch req.Method {
+	case "initialize":
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync: TextDocumentSyncOptions{OpenClose: true, Change: syncFull, Save: true},
+				HoverProvider:    true,
+			},
+			ServerInfo: ServerInfo{Name: "pint"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.lock.Lock()
+		s.shutdown = true
+		s.lock.Unlock()
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: invalidParams, Message: err.Error()}
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Version, []byte(params.TextDocument.Text), 0)
+		return nil, nil
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: invalidParams, Message: err.Error()}
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// we only support full document sync, so last change has the whole content
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.update(params.TextDocument.URI, params.TextDocument.Version, []byte(text), s.debounce)
+		return nil, nil
+	case "textDocument/didSave", "workspace/didChangeWatchedFiles":
+		if s.refresh != nil {
+			s.refresh()
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: invalidParams, Message: err.Error()}
+		}
+		s.close(params.TextDocument.URI)
+		return nil, nil
+	case "textDocument/hover":
+		var params HoverParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &responseError{Code: invalidParams, Message: err.Error()}
+		}
+		return s.hover(params), nil
+	}
+
+	if req.isNotification() {
+		return nil, nil
+	}
+	return nil, &responseError{Code: methodNotFound, Message: fmt.Sprintf("unsupported method: %s", req.Method)}
+}
+
+func (s *Server) reply(id json.RawMessage, result interface{}, rerr *responseError) error {
+	resp := response{JSONRPC: "2.0", ID: id, Error: rerr}
+	if id == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	if rerr == nil {
+		content, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = content
+	}
+	return s.send(resp)
+}
+
+func (s *Server) send(msg interface{}) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return writeMessage(s.out, msg)
+}
+
+func (s *Server) update(uri string, version int, content []byte, delay time.Duration) {
+	s.lock.Lock()
+	doc, ok := s.docs[uri]
+	if !ok {
+		doc = &document{uri: uri}
+		s.docs[uri] = doc
+	}
+	doc.version = version
+	doc.content = content
+	if doc.timer != nil {
+		doc.timer.Stop()
+		doc.timer = nil
+	}
+	if s.debounce > 0 {
+		doc.timer = time.AfterFunc(delay, func() { s.check(uri, version) })
+	}
+	s.lock.Unlock()
+
+	if s.debounce == 0 {
+		s.check(uri, version)
+	}
+}
+
+func (s *Server) check(uri string, version int) {
+	s.lock.Lock()
+	doc, ok := s.docs[uri]
+	if !ok || doc.version != version {
+		s.lock.Unlock()
+		return
+	}
+	content := doc.content
+	s.lock.Unlock()
+
+	path, err := uriToPath(uri)
+	if err != nil {
+		log.Error().Err(err).Str("uri", uri).Msg("Unsupported document URI")
+		return
+	}
+
+	log.Debug().Str("path", path).Int("version", version).Msg("Running checks")
+	problems := s.lint(path, content)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// document was closed or modified while checks were running
+	if doc, ok = s.docs[uri]; !ok || doc.version != version {
+		return
+	}
+	doc.problems = problems
+	s.publish(doc)
+}
+
+func (s *Server) close(uri string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	doc, ok := s.docs[uri]
+	if !ok {
+		return
+	}
+	if doc.timer != nil {
+		doc.timer.Stop()
+	}
+	delete(s.docs, uri)
+
+	doc.problems = nil
+	s.publish(doc)
+}
+
+func (s *Server) stopTimers() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, doc := range s.docs {
+		if doc.timer != nil {
+			doc.timer.Stop()
+		}
+	}
+}
+
+// publish must be called with s.lock held so diagnostics for older versions
+// are never sent after diagnostics for newer ones
+func (s *Server) publish(doc *document) {
+	lines := strings.Split(string(doc.content), "\n")
+	diags := []Diagnostic{}
+	for _, problem := range doc.problems {
+		diags = append(diags, Diagnostic{
+			Range:    problemRange(lines, problem),
+			Severity: diagnosticSeverity(problem.Severity),
+			Code:     problem.Reporter,
+			Source:   "pint",
+			Message:  problem.Text,
+		})
+	}
+
+	err := s.send(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  PublishDiagnosticsParams{URI: doc.uri, Version: doc.version, Diagnostics: diags},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("uri", doc.uri).Msg("Failed to publish diagnostics")
+	}
+}
+
+func (s *Server) hover(params HoverParams) *Hover {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	line := params.Position.Line + 1
+	parts := []string{}
+	for _, problem := range doc.problems {
+		first, last := problem.LineRange()
+		if line < first || line > last {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("**%s** (%s)\n\n%s", problem.Reporter, problem.Severity, problem.Text))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: strings.Join(parts, "\n\n---\n\n"),
+		},
+	}
+}
+
+func problemRange(lines []string, problem checks.Problem) Range {
+	first, last := problem.LineRange()
+	r := Range{
+		Start: Position{Line: first - 1},
+		End:   Position{Line: last - 1},
+	}
+	if last >= 1 && last <= len(lines) {
+		// positions are counted in UTF-16 code units
+		r.End.Character = len(utf16.Encode([]rune(strings.TrimRight(lines[last-1], "\r"))))
+	}
+	return r
+}
+
+func diagnosticSeverity(s checks.Severity) DiagnosticSeverity {
+	switch s {
+	case checks.Information:
+		return SeverityInformation
+	case checks.Warning:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI scheme %q", u.Scheme)
+	}
+	return u.Path, nil
+}
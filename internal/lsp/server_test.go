@@ -0,0 +1,249 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func encodeMessages(t *testing.T, msgs ...string) io.Reader {
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		if err := writeMessage(&buf, json.RawMessage(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &buf
+}
+
+func decodeMessages(t *testing.T, r io.Reader) (msgs []string) {
+	br := bufio.NewReader(r)
+	for {
+		body, err := readMessage(br)
+		if errors.Is(err, io.EOF) {
+			return msgs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, string(body))
+	}
+}
+
+func TestServe(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	type testCaseT struct {
+		description string
+		input       []string
+		problems    []checks.Problem
+		output      []string
+		shouldError bool
+	}
+
+	problems := []checks.Problem{
+		{
+			Lines:    []int{2},
+			Reporter: "promql/rate",
+			Text:     "duration for rate() is too small",
+			Severity: checks.Bug,
+		},
+		{
+			Lines:    []int{1, 2},
+			Reporter: "promql/by",
+			Text:     "job label is required",
+			Severity: checks.Warning,
+		},
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "initialize",
+			input: []string{
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+				`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+				`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`,
+				`{"jsonrpc":"2.0","method":"exit"}`,
+			},
+			output: []string{
+				`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"textDocumentSync":{"openClose":true,"change":1,"save":true},"hoverProvider":true},"serverInfo":{"name":"pint"}}}`,
+				`{"jsonrpc":"2.0","id":2,"result":null}`,
+			},
+		},
+		{
+			description: "exit without shutdown",
+			input: []string{
+				`{"jsonrpc":"2.0","method":"exit"}`,
+			},
+			shouldError: true,
+		},
+		{
+			description: "unsupported method",
+			input: []string{
+				`{"jsonrpc":"2.0","id":"a","method":"textDocument/completion","params":{}}`,
+				`{"jsonrpc":"2.0","method":"$/cancelRequest","params":{}}`,
+			},
+			output: []string{
+				`{"jsonrpc":"2.0","id":"a","error":{"code":-32601,"message":"unsupported method: textDocument/completion"}}`,
+			},
+		},
+		{
+			description: "diagnostics and hover",
+			input: []string{
+				`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///rules.yml","languageId":"yaml","version":1,"text":"- record: foo\n  expr: rate(foo[1m]) # ✓\n"}}}`,
+				`{"jsonrpc":"2.0","id":1,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///rules.yml"},"position":{"line":1,"character":4}}}`,
+				`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///rules.yml"},"position":{"line":5,"character":0}}}`,
+				`{"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":"file:///rules.yml"}}}`,
+			},
+			problems: problems,
+			output: []string{
+				`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///rules.yml","version":1,"diagnostics":[{"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":25}},"severity":1,"code":"promql/rate","source":"pint","message":"duration for rate() is too small"},{"range":{"start":{"line":0,"character":0},"end":{"line":1,"character":25}},"severity":2,"code":"promql/by","source":"pint","message":"job label is required"}]}}`,
+				`{"jsonrpc":"2.0","id":1,"result":{"contents":{"kind":"markdown","value":"**promql/rate** (Bug)\n\nduration for rate() is too small\n\n---\n\n**promql/by** (Warning)\n\njob label is required"}}}`,
+				`{"jsonrpc":"2.0","id":2,"result":null}`,
+				`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///rules.yml","version":1,"diagnostics":[]}}`,
+			},
+		},
+		{
+			description: "changes",
+			input: []string{
+				`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///rules.yml","languageId":"yaml","version":1,"text":"- record: foo\n"}}}`,
+				`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///rules.yml","version":2},"contentChanges":[{"text":"- record: bar\n"}]}}`,
+				`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"http://example.com/rules.yml","version":1},"contentChanges":[{"text":"- record: bar\n"}]}}`,
+			},
+			output: []string{
+				`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///rules.yml","version":1,"diagnostics":[]}}`,
+				`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///rules.yml","version":2,"diagnostics":[]}}`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv := NewServer(func(path string, content []byte) []checks.Problem {
+				if path != "/rules.yml" {
+					t.Errorf("lint called with unexpected path %q", path)
+				}
+				return tc.problems
+			}, nil, 0)
+
+			var out bytes.Buffer
+			err := srv.Serve(encodeMessages(t, tc.input...), &out)
+			hadError := err != nil
+			if hadError != tc.shouldError {
+				t.Fatalf("Serve() returned err=%v, expected=%v", err, tc.shouldError)
+			}
+
+			if diff := cmp.Diff(tc.output, decodeMessages(t, &out)); diff != "" {
+				t.Errorf("Serve() returned wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) Bytes() []byte {
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+	return sb.buf.Bytes()
+}
+
+func TestServeDebounce(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	var lock sync.Mutex
+	checked := []string{}
+	srv := NewServer(func(path string, content []byte) []checks.Problem {
+		lock.Lock()
+		defer lock.Unlock()
+		checked = append(checked, string(content))
+		return nil
+	}, nil, time.Millisecond*100)
+
+	in, w := io.Pipe()
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- srv.Serve(in, out)
+	}()
+
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///rules.yml","languageId":"yaml","version":1,"text":"a"}}}`,
+	} {
+		if err := writeMessage(w, json.RawMessage(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(time.Millisecond * 50)
+
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///rules.yml","version":2},"contentChanges":[{"text":"ab"}]}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///rules.yml","version":3},"contentChanges":[{"text":"abc"}]}}`,
+	} {
+		if err := writeMessage(w, json.RawMessage(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(time.Millisecond * 300)
+
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if diff := cmp.Diff([]string{"a", "abc"}, checked); diff != "" {
+		t.Errorf("wrong content was checked (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{
+		`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///rules.yml","version":1,"diagnostics":[]}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///rules.yml","version":3,"diagnostics":[]}}`,
+	}, decodeMessages(t, bytes.NewReader(out.Bytes()))); diff != "" {
+		t.Errorf("Serve() returned wrong output (-want +got):\n%s", diff)
+	}
+}
+
+func TestServeRefresh(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	var refreshed int
+	srv := NewServer(func(path string, content []byte) []checks.Problem {
+		return nil
+	}, func() {
+		refreshed++
+	}, 0)
+
+	var out bytes.Buffer
+	err := srv.Serve(encodeMessages(t,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///rules.yml","languageId":"yaml","version":1,"text":"a"}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///rules.yml","version":2},"contentChanges":[{"text":"ab"}]}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didSave","params":{"textDocument":{"uri":"file:///rules.yml"}}}`,
+		`{"jsonrpc":"2.0","method":"workspace/didChangeWatchedFiles","params":{"changes":[{"uri":"file:///other.yml","type":2}]}}`,
+	), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed != 2 {
+		t.Errorf("expected refresh to be called 2 times, got %d", refreshed)
+	}
+}
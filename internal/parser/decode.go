@@ -21,18 +21,22 @@ func decodeExpr(expr string) (*PromQLNode, error) {
 		return nil, pqe
 	}
 
+	return newPromQLNode(expr, node), nil
+}
+
+// newPromQLNode builds a tree of PromQLNode using nodes from the original
+// query, so position ranges of every node are relative to the parsed string
+func newPromQLNode(expr string, node promparser.Node) *PromQLNode {
 	pn := PromQLNode{
 		Expr: expr,
-		Node: node,
+	}
+	if e, ok := node.(promparser.Expr); ok {
+		pn.Node = e
 	}
 
 	for _, child := range promparser.Children(node) {
-		c, err := decodeExpr(child.String())
-		if err != nil {
-			return nil, err
-		}
-		pn.Children = append(pn.Children, c)
+		pn.Children = append(pn.Children, newPromQLNode(child.String(), child))
 	}
 
-	return &pn, nil
+	return &pn
 }
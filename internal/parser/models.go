@@ -194,6 +194,11 @@ type PromQLExpr struct {
 	Value       *YamlNode
 	SyntaxError error
 	Query       *PromQLNode
+	// Source is the content of all lines with this expression as it is in
+	// the file, before any YAML decoding, and SourceOffset is the position
+	// in Source where the value starts
+	Source       string
+	SourceOffset int
 }
 
 func (pqle PromQLExpr) Lines() (lines []int) {
@@ -202,6 +207,16 @@ func (pqle PromQLExpr) Lines() (lines []int) {
 	return
 }
 
+// Normalized returns the query formatted by the PromQL parser, so any
+// whitespace differences are ignored, queries with syntax errors are
+// returned as is
+func (pqle PromQLExpr) Normalized() string {
+	if pqle.SyntaxError != nil || pqle.Query == nil || pqle.Query.Node == nil {
+		return pqle.Value.Value
+	}
+	return pqle.Query.Node.String()
+}
+
 func newPromQLExpr(key, val *yaml.Node) *PromQLExpr {
 	expr := PromQLExpr{
 		Key:   newYamlNode(key),
@@ -303,10 +318,60 @@ type ParseError struct {
 	Line     int
 }
 
+// RuleGroup is a group of rules, it's any mapping with a list of rules under
+// the rules key
+type RuleGroup struct {
+	Name                    *YamlKeyValue
+	Interval                *YamlKeyValue
+	Limit                   *YamlKeyValue
+	PartialResponseStrategy *YamlKeyValue
+	// UnknownKeys are all keys that are not valid for a group
+	UnknownKeys []*YamlNode
+	// Line is where the group definition starts
+	Line int
+	// Document is the index of the YAML document this group was defined in
+	Document int
+	// Kubernetes is the object this group was defined in, if any
+	Kubernetes *KubernetesObject
+}
+
+func (g RuleGroup) Lines() (lines []int) {
+	for _, kv := range []*YamlKeyValue{g.Name, g.Interval, g.Limit, g.PartialResponseStrategy} {
+		if kv != nil {
+			lines = appendLine(lines, kv.Lines()...)
+		}
+	}
+	for _, key := range g.UnknownKeys {
+		lines = appendLine(lines, key.Position.Lines...)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, g.Line)
+	}
+	return lines
+}
+
+// KubernetesObject holds metadata of the PrometheusRule object that
+// rules were defined in
+type KubernetesObject struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Labels     map[string]string
+	// Line is where the object definition starts
+	Line int
+}
+
 type Rule struct {
 	AlertingRule  *AlertingRule
 	RecordingRule *RecordingRule
 	Error         ParseError
+	// Group is the rule group this rule was defined in, it's nil for rules
+	// that are not part of any group
+	Group *RuleGroup
+	// Kubernetes is the object this rule was defined in, it's nil for rules
+	// from plain Prometheus rule files
+	Kubernetes *KubernetesObject
 }
 
 func (r Rule) Expr() PromQLExpr {
@@ -316,6 +381,16 @@ func (r Rule) Expr() PromQLExpr {
 	return r.AlertingRule.Expr
 }
 
+func (r Rule) Name() string {
+	if r.RecordingRule != nil {
+		return r.RecordingRule.Record.Value.Value
+	}
+	if r.AlertingRule != nil {
+		return r.AlertingRule.Alert.Value.Value
+	}
+	return ""
+}
+
 func (r Rule) Lines() []int {
 	if r.RecordingRule != nil {
 		return r.RecordingRule.Lines()
@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -14,6 +17,15 @@ const (
 	alertKey       = "alert"
 	forKey         = "for"
 	annotationsKey = "annotations"
+
+	rulesKey                        = "rules"
+	groupNameKey                    = "name"
+	groupIntervalKey                = "interval"
+	groupLimitKey                   = "limit"
+	groupPartialResponseStrategyKey = "partial_response_strategy"
+
+	prometheusRuleGroup = "monitoring.coreos.com"
+	prometheusRuleKind  = "PrometheusRule"
 )
 
 func NewParser() Parser {
@@ -23,34 +35,136 @@ func NewParser() Parser {
 type Parser struct{}
 
 func (p Parser) Parse(content []byte) (rules []Rule, err error) {
+	rules, _, err = p.parse(content)
+	return rules, err
+}
+
+// ParseWithGroups returns all rules and all rule groups found in given
+// content, including groups without any rules
+func (p Parser) ParseWithGroups(content []byte) (rules []Rule, groups []RuleGroup, err error) {
+	rules, gs, err := p.parse(content)
+	for _, g := range gs {
+		groups = append(groups, *g)
+	}
+	return rules, groups, err
+}
+
+func (p Parser) parse(content []byte) (rules []Rule, groups []*RuleGroup, err error) {
 	if len(content) == 0 {
 		return
 	}
 
-	var node yaml.Node
-	err = yaml.Unmarshal(content, &node)
-	if err != nil {
-		return nil, err
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for index := 0; ; index++ {
+		var node yaml.Node
+		err = dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		obj := parseKubernetesObject(&node)
+
+		var docGroups []*RuleGroup
+		docRules, err := parseNode(content, &node, nil, &docGroups)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range docRules {
+			docRules[i].Kubernetes = obj
+		}
+		for _, g := range docGroups {
+			g.Document = index
+			g.Kubernetes = obj
+		}
+		rules = append(rules, docRules...)
+		groups = append(groups, docGroups...)
+	}
+
+	return rules, groups, nil
+}
+
+// parseKubernetesObject returns metadata of the PrometheusRule object defined
+// in given YAML document, it returns nil for any other document
+func parseKubernetesObject(doc *yaml.Node) *KubernetesObject {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	root := doc.Content[0]
+
+	if !IsPrometheusRule(root) {
+		return nil
+	}
+
+	obj := KubernetesObject{
+		APIVersion: MappingValue(root, "apiVersion").Value,
+		Kind:       MappingValue(root, "kind").Value,
+		Labels:     map[string]string{},
+		Line:       root.Line,
+	}
+	if metadata := MappingValue(root, "metadata"); metadata != nil {
+		if name := MappingValue(metadata, "name"); name != nil {
+			obj.Name = name.Value
+		}
+		if namespace := MappingValue(metadata, "namespace"); namespace != nil {
+			obj.Namespace = namespace.Value
+		}
+		if labels := MappingValue(metadata, "labels"); labels != nil && labels.Kind == yaml.MappingNode {
+			for i := 0; i < len(labels.Content)-1; i += 2 {
+				obj.Labels[labels.Content[i].Value] = labels.Content[i+1].Value
+			}
+		}
+	}
+	return &obj
+}
+
+// IsPrometheusRule returns true if given mapping node is a PrometheusRule
+// Kubernetes object
+func IsPrometheusRule(root *yaml.Node) bool {
+	apiVersion := MappingValue(root, "apiVersion")
+	kind := MappingValue(root, "kind")
+	if apiVersion == nil || kind == nil {
+		return false
 	}
+	return strings.HasPrefix(apiVersion.Value, prometheusRuleGroup+"/") && kind.Value == prometheusRuleKind
+}
 
-	return parseNode(content, &node)
+// MappingValue returns the value of given key in a mapping node, or nil if
+// the node is not a mapping or there's no such key
+func MappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
 }
 
-func parseNode(content []byte, node *yaml.Node) (rules []Rule, err error) {
+func parseNode(content []byte, node *yaml.Node, group *RuleGroup, groups *[]*RuleGroup) (rules []Rule, err error) {
 	ret, isEmpty, err := parseRule(content, node)
 	if err != nil {
 		return nil, err
 	}
 	if !isEmpty {
+		ret.Group = group
 		rules = append(rules, ret)
 		return
 	}
+	if g := parseGroup(node); g != nil {
+		group = g
+		*groups = append(*groups, g)
+	}
 
 	for _, root := range node.Content {
 		switch root.Kind {
 		case yaml.SequenceNode:
 			for _, n := range root.Content {
-				ret, err := parseNode(content, n)
+				ret, err := parseNode(content, n, group, groups)
 				if err != nil {
 					return nil, err
 				}
@@ -62,10 +176,16 @@ func parseNode(content []byte, node *yaml.Node) (rules []Rule, err error) {
 				return nil, err
 			}
 			if !isEmpty {
+				rule.Group = group
 				rules = append(rules, rule)
 			} else {
+				rootGroup := group
+				if g := parseGroup(root); g != nil {
+					rootGroup = g
+					*groups = append(*groups, g)
+				}
 				for _, n := range root.Content {
-					ret, err := parseNode(content, n)
+					ret, err := parseNode(content, n, rootGroup, groups)
 					if err != nil {
 						return nil, err
 					}
@@ -77,6 +197,43 @@ func parseNode(content []byte, node *yaml.Node) (rules []Rule, err error) {
 	return rules, nil
 }
 
+// parseGroup returns a rule group if given node is a mapping with a list
+// of rules, it returns nil otherwise
+func parseGroup(node *yaml.Node) *RuleGroup {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var isGroup bool
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == rulesKey && node.Content[i+1].Kind == yaml.SequenceNode {
+			isGroup = true
+		}
+	}
+	if !isGroup {
+		return nil
+	}
+
+	group := RuleGroup{Line: node.Line}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case rulesKey:
+		case groupNameKey:
+			group.Name = newYamlKeyValue(key, val)
+		case groupIntervalKey:
+			group.Interval = newYamlKeyValue(key, val)
+		case groupLimitKey:
+			group.Limit = newYamlKeyValue(key, val)
+		case groupPartialResponseStrategyKey:
+			group.PartialResponseStrategy = newYamlKeyValue(key, val)
+		default:
+			group.UnknownKeys = append(group.UnknownKeys, newYamlNode(key))
+		}
+	}
+	return &group
+}
+
 func parseRule(content []byte, node *yaml.Node) (rule Rule, isEmpty bool, err error) {
 	isEmpty = true
 
@@ -86,6 +243,7 @@ func parseRule(content []byte, node *yaml.Node) (rule Rule, isEmpty bool, err er
 
 	var recordPart *YamlKeyValue
 	var exprPart *PromQLExpr
+	var exprValue *yaml.Node
 	var labelsPart *YamlMap
 
 	var alertPart *YamlKeyValue
@@ -111,6 +269,7 @@ func parseRule(content []byte, node *yaml.Node) (rule Rule, isEmpty bool, err er
 				alertPart = newYamlKeyValue(key, part)
 			case exprKey:
 				exprPart = newPromQLExpr(key, part)
+				exprValue = part
 			case forKey:
 				forPart = newYamlKeyValue(key, part)
 			case labelsKey:
@@ -141,6 +300,10 @@ func parseRule(content []byte, node *yaml.Node) (rule Rule, isEmpty bool, err er
 		}
 	}
 
+	if exprPart != nil {
+		exprPart.Source, exprPart.SourceOffset = exprSource(content, exprPart, exprValue)
+	}
+
 	if recordPart != nil && alertPart != nil {
 		isEmpty = false
 		rule = Rule{
@@ -220,3 +383,32 @@ func parseRule(content []byte, node *yaml.Node) (rule Rule, isEmpty bool, err er
 
 	return
 }
+
+// exprSource returns raw content of all lines with given expression and
+// the offset in it where the value starts
+func exprSource(content []byte, expr *PromQLExpr, value *yaml.Node) (string, int) {
+	lines := strings.SplitAfter(string(content), "\n")
+	first, last := expr.Key.Position.FistLine(), expr.Value.Position.LastLine()
+	if first < 1 || last > len(lines) || first > last {
+		return "", 0
+	}
+	source := strings.Join(lines[first-1:last], "")
+	if !strings.HasSuffix(source, "\n") {
+		source += "\n"
+	}
+
+	var offset int
+	line, column := value.Line, value.Column
+	if value.Style == yaml.LiteralStyle || value.Style == yaml.FoldedStyle {
+		// block scalar value starts on the line after the indicator
+		line, column = line+1, 1
+	}
+	for l := first; l < line && l <= last; l++ {
+		offset += len(lines[l-1])
+	}
+	offset += column - 1
+	if offset > len(source) {
+		return source, len(source)
+	}
+	return source, offset
+}
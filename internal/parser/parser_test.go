@@ -105,6 +105,8 @@ func TestParse(t *testing.T) {
 							Query: &parser.PromQLNode{
 								Expr: "foo offset 10m",
 							},
+							Source:       "  expr: foo offset 10m\n",
+							SourceOffset: 8,
 						},
 					},
 				},
@@ -151,6 +153,8 @@ func TestParse(t *testing.T) {
 							Query: &parser.PromQLNode{
 								Expr: "foo offset 10m",
 							},
+							Source:       "  expr: foo offset 10m # expr comment\n",
+							SourceOffset: 8,
 						},
 						Labels: &parser.YamlMap{
 							Key: &parser.YamlNode{
@@ -217,6 +221,8 @@ func TestParse(t *testing.T) {
 									{Expr: "foo offset 10m"},
 								},
 							},
+							Source:       "  expr: foo[5m] offset 10m\n",
+							SourceOffset: 8,
 						},
 					},
 				},
@@ -258,6 +264,8 @@ func TestParse(t *testing.T) {
 									{Expr: "foo"},
 								},
 							},
+							Source:       "  expr: sum(foo)\n",
+							SourceOffset: 8,
 						},
 						Labels: &parser.YamlMap{
 							Key: &parser.YamlNode{
@@ -331,6 +339,8 @@ groups:
 									{Expr: "foo"},
 								},
 							},
+							Source:       "      expr: sum(foo)\n",
+							SourceOffset: 12,
 						},
 						Labels: &parser.YamlMap{
 							Key: &parser.YamlNode{
@@ -361,6 +371,19 @@ groups:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "custom_rules",
+							},
+						},
+						Line: 3,
+					},
 				},
 			},
 			shouldError: false,
@@ -413,6 +436,8 @@ groups:
 									{Expr: "0"},
 								},
 							},
+							Source:       "  expr: |\n    up == 0\n",
+							SourceOffset: 10,
 						},
 						For: &parser.YamlKeyValue{
 							Key: &parser.YamlNode{
@@ -493,6 +518,8 @@ groups:
 									{Expr: "1"},
 								},
 							},
+							Source:       "  expr: |-\n    bar\n    /\n    baz > 1\n",
+							SourceOffset: 11,
 						},
 						Labels: &parser.YamlMap{
 							Key: &parser.YamlNode{
@@ -572,6 +599,8 @@ groups:
 									{Expr: "baz"},
 								},
 							},
+							Source:       "  expr:\n    (\n      xxx\n      -\n      yyy\n    ) * bar > 0\n    and on(instance, device) baz\n",
+							SourceOffset: 12,
 						},
 						For: &parser.YamlKeyValue{
 							Key: &parser.YamlNode{
@@ -651,3 +680,217 @@ data:
 		})
 	}
 }
+
+func TestParseWithGroups(t *testing.T) {
+	type testCaseT struct {
+		description string
+		content     string
+		output      []parser.RuleGroup
+		ruleGroups  []string
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no groups",
+			content:     "- record: foo\n  expr: bar\n",
+			ruleGroups:  []string{""},
+		},
+		{
+			description: "groups",
+			content: `groups:
+- name: foo
+  interval: 1m
+  limit: 10
+  partial_response_strategy: warn
+  bogus: 1
+  rules:
+  - record: foo
+    expr: bar
+- name: empty
+  rules: []
+- name: bar
+  rules:
+  - alert: foo
+    expr: bar
+`,
+			output: []parser.RuleGroup{
+				{
+					Name: &parser.YamlKeyValue{
+						Key:   &parser.YamlNode{Position: parser.FilePosition{Lines: []int{2}}, Value: "name"},
+						Value: &parser.YamlNode{Position: parser.FilePosition{Lines: []int{2}}, Value: "foo"},
+					},
+					Interval: &parser.YamlKeyValue{
+						Key:   &parser.YamlNode{Position: parser.FilePosition{Lines: []int{3}}, Value: "interval"},
+						Value: &parser.YamlNode{Position: parser.FilePosition{Lines: []int{3}}, Value: "1m"},
+					},
+					Limit: &parser.YamlKeyValue{
+						Key:   &parser.YamlNode{Position: parser.FilePosition{Lines: []int{4}}, Value: "limit"},
+						Value: &parser.YamlNode{Position: parser.FilePosition{Lines: []int{4}}, Value: "10"},
+					},
+					PartialResponseStrategy: &parser.YamlKeyValue{
+						Key:   &parser.YamlNode{Position: parser.FilePosition{Lines: []int{5}}, Value: "partial_response_strategy"},
+						Value: &parser.YamlNode{Position: parser.FilePosition{Lines: []int{5}}, Value: "warn"},
+					},
+					UnknownKeys: []*parser.YamlNode{
+						{Position: parser.FilePosition{Lines: []int{6}}, Value: "bogus"},
+					},
+					Line: 2,
+				},
+				{
+					Name: &parser.YamlKeyValue{
+						Key:   &parser.YamlNode{Position: parser.FilePosition{Lines: []int{10}}, Value: "name"},
+						Value: &parser.YamlNode{Position: parser.FilePosition{Lines: []int{10}}, Value: "empty"},
+					},
+					Line: 10,
+				},
+				{
+					Name: &parser.YamlKeyValue{
+						Key:   &parser.YamlNode{Position: parser.FilePosition{Lines: []int{12}}, Value: "name"},
+						Value: &parser.YamlNode{Position: parser.FilePosition{Lines: []int{12}}, Value: "bar"},
+					},
+					Line: 12,
+				},
+			},
+			ruleGroups: []string{"foo", "bar"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			p := parser.NewParser()
+			_, groups, err := p.ParseWithGroups([]byte(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.output, groups); diff != "" {
+				t.Errorf("ParseWithGroups() returned wrong output (-want +got):\n%s", diff)
+			}
+
+			rules, err := p.Parse([]byte(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ruleGroups := []string{}
+			for _, rule := range rules {
+				var name string
+				if rule.Group != nil && rule.Group.Name != nil {
+					name = rule.Group.Name.Value.Value
+				}
+				ruleGroups = append(ruleGroups, name)
+			}
+			if diff := cmp.Diff(tc.ruleGroups, ruleGroups); diff != "" {
+				t.Errorf("Parse() returned rules with wrong groups (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseMultipleDocuments(t *testing.T) {
+	type ruleT struct {
+		Name       string
+		Lines      []int
+		Kubernetes *parser.KubernetesObject
+	}
+
+	type testCaseT struct {
+		description string
+		content     string
+		rules       []ruleT
+		shouldError bool
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "plain rules",
+			content:     "---\n- record: foo\n  expr: sum(foo)\n---\n- record: bar\n  expr: sum(bar)\n",
+			rules: []ruleT{
+				{Name: "foo", Lines: []int{2, 3}},
+				{Name: "bar", Lines: []int{5, 6}},
+			},
+		},
+		{
+			description: "PrometheusRule objects",
+			content: `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+  namespace: monitoring
+  labels:
+    team: infra
+spec:
+  groups:
+  - name: foo
+    rules:
+    - record: foo
+      expr: sum(foo)
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bar
+data:
+  foo: bar
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: bar
+spec:
+  groups:
+  - name: foo
+    rules:
+    - alert: bar
+      expr: up == 0
+`,
+			rules: []ruleT{
+				{
+					Name:  "foo",
+					Lines: []int{12, 13},
+					Kubernetes: &parser.KubernetesObject{
+						APIVersion: "monitoring.coreos.com/v1",
+						Kind:       "PrometheusRule",
+						Namespace:  "monitoring",
+						Name:       "foo",
+						Labels:     map[string]string{"team": "infra"},
+						Line:       1,
+					},
+				},
+				{
+					Name:  "bar",
+					Lines: []int{30, 31},
+					Kubernetes: &parser.KubernetesObject{
+						APIVersion: "monitoring.coreos.com/v1",
+						Kind:       "PrometheusRule",
+						Name:       "bar",
+						Labels:     map[string]string{},
+						Line:       22,
+					},
+				},
+			},
+		},
+		{
+			description: "error in second document",
+			content:     "- record: foo\n  expr: sum(foo)\n---\n- record: [\n",
+			shouldError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			p := parser.NewParser()
+			rules, err := p.Parse([]byte(tc.content))
+			hadError := err != nil
+			if hadError != tc.shouldError {
+				t.Fatalf("Parse() returned err=%v, expected=%v", err, tc.shouldError)
+			}
+
+			var output []ruleT
+			for _, rule := range rules {
+				output = append(output, ruleT{Name: rule.Name(), Lines: rule.Lines(), Kubernetes: rule.Kubernetes})
+			}
+			if diff := cmp.Diff(tc.rules, output); diff != "" {
+				t.Errorf("Parse() returned wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -34,8 +34,10 @@ func Config(uri string, timeout time.Duration) (*PrometheusConfig, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	prometheusQueriesTotal.WithLabelValues("/api/v1/status/config").Inc()
 	resp, err := v1api.Config(ctx)
 	if err != nil {
+		prometheusQueryErrorsTotal.WithLabelValues("/api/v1/status/config").Inc()
 		log.Error().Err(err).Str("uri", uri).Msg("Failed to query Prometheus configuration")
 		return nil, fmt.Errorf("failed to query Prometheus config: %v", err)
 	}
@@ -0,0 +1,73 @@
+package promapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/rs/zerolog/log"
+)
+
+// metadata doesn't change often, so we cache it for a while to avoid
+// sending the same request for every rule using given metric
+const metadataCacheTTL = time.Minute * 5
+
+type metadataCacheEntry struct {
+	result  *MetadataResult
+	expires time.Time
+}
+
+var (
+	metadataCache     = map[string]metadataCacheEntry{}
+	metadataCacheLock sync.Mutex
+)
+
+type MetadataResult struct {
+	Metadata []v1.Metadata
+}
+
+// Metadata returns metadata for given metric, results are cached per
+// Prometheus server and metric name
+func Metadata(uri string, timeout time.Duration, metric string) (*MetadataResult, error) {
+	key := fmt.Sprintf("metadata|%s|%s", uri, metric)
+	km.Lock(key)
+	defer km.Unlock(key)
+
+	metadataCacheLock.Lock()
+	entry, ok := metadataCache[key]
+	metadataCacheLock.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		log.Debug().Str("uri", uri).Str("metric", metric).Msg("Using cached metric metadata")
+		return entry.result, nil
+	}
+
+	log.Debug().Str("uri", uri).Str("metric", metric).Msg("Query Prometheus metric metadata")
+
+	client, err := api.NewClient(api.Config{Address: uri})
+	if err != nil {
+		return nil, err
+	}
+
+	v1api := v1.NewAPI(client)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	prometheusQueriesTotal.WithLabelValues("/api/v1/metadata").Inc()
+	resp, err := v1api.Metadata(ctx, metric, "")
+	if err != nil {
+		prometheusQueryErrorsTotal.WithLabelValues("/api/v1/metadata").Inc()
+		log.Error().Err(err).Str("uri", uri).Str("metric", metric).Msg("Failed to query Prometheus metric metadata")
+		return nil, fmt.Errorf("failed to query Prometheus metric metadata: %v", err)
+	}
+
+	result := MetadataResult{Metadata: resp[metric]}
+
+	metadataCacheLock.Lock()
+	metadataCache[key] = metadataCacheEntry{result: &result, expires: time.Now().Add(metadataCacheTTL)}
+	metadataCacheLock.Unlock()
+
+	return &result, nil
+}
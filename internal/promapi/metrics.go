@@ -0,0 +1,27 @@
+package promapi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	prometheusQueriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_queries_total",
+			Help: "Total number of all prometheus queries",
+		},
+		[]string{"endpoint"},
+	)
+	prometheusQueryErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_query_errors_total",
+			Help: "Total number of failed prometheus queries",
+		},
+		[]string{"endpoint"},
+	)
+)
+
+// RegisterMetrics will register all prometheus API metrics with given registry
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(prometheusQueriesTotal, prometheusQueryErrorsTotal)
+}
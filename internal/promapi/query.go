@@ -43,6 +43,7 @@ func Query(uri string, timeout time.Duration, expr string, lockKey *string) (*Qu
 	defer cancel()
 
 	start := time.Now()
+	prometheusQueriesTotal.WithLabelValues("/api/v1/query").Inc()
 	result, _, err := v1api.Query(ctx, expr, start)
 	duration := time.Since(start)
 	log.Debug().
@@ -51,6 +52,7 @@ func Query(uri string, timeout time.Duration, expr string, lockKey *string) (*Qu
 		Str("duration", HumanizeDuration(duration)).
 		Msg("Query completed")
 	if err != nil {
+		prometheusQueryErrorsTotal.WithLabelValues("/api/v1/query").Inc()
 		log.Error().Err(err).
 			Str("uri", uri).
 			Str("query", expr).
@@ -55,6 +55,7 @@ func RangeQuery(uri string, timeout time.Duration, expr string, start, end time.
 		Step:  step,
 	}
 	qstart := time.Now()
+	prometheusQueriesTotal.WithLabelValues("/api/v1/query_range").Inc()
 	result, _, err := v1api.QueryRange(ctx, expr, r)
 	duration := time.Since(qstart)
 	log.Debug().
@@ -63,6 +64,7 @@ func RangeQuery(uri string, timeout time.Duration, expr string, start, end time.
 		Str("duration", HumanizeDuration(duration)).
 		Msg("Range query completed")
 	if err != nil {
+		prometheusQueryErrorsTotal.WithLabelValues("/api/v1/query_range").Inc()
 		log.Error().Err(err).Str("uri", uri).Str("query", expr).Msg("Range query failed")
 		if err, ok := err.(net.Error); ok && err.Timeout() {
 			delta := end.Sub(start) / 2
@@ -60,7 +60,7 @@ func (r BitBucketReporter) Submit(summary Summary) (err error) {
 	}
 	log.Info().Str("commit", headCommit).Msg("Got HEAD commit from git")
 
-	pb, err := r.blameReports(summary.Reports)
+	pb, err := blameReports(summary.Reports, r.gitCmd)
 	if err != nil {
 		return fmt.Errorf("failed to run git blame: %w", err)
 	}
@@ -89,54 +89,14 @@ func (r BitBucketReporter) Submit(summary Summary) (err error) {
 	return nil
 }
 
-func (r BitBucketReporter) blameReports(reports []Report) (pb git.FileBlames, err error) {
-	pb = make(git.FileBlames)
-	for _, report := range reports {
-		if _, ok := pb[report.Path]; ok {
-			continue
-		}
-		pb[report.Path], err = git.Blame(report.Path, r.gitCmd)
-		if err != nil {
-			return
-		}
-	}
-	return
-}
-
 func (r BitBucketReporter) makeAnnotation(report Report, summary Summary, pb git.FileBlames) (annotations []BitBucketAnnotation) {
-	gitBlames, ok := pb[report.Path]
-	if !ok {
-		return
-	}
-
-	reportLine := -1
-	for _, pl := range report.Problem.Lines {
-		commit := gitBlames.GetCommit(pl)
-		if summary.FileChanges.HasCommit(commit) {
-			reportLine = pl
-		}
-	}
-
-	if reportLine < 0 && report.Problem.Severity == checks.Fatal {
-		for _, fl := range summary.FileChanges.Results() {
-			if fl.Path != report.Path {
-				continue
-			}
-			for _, commit := range fl.Commits {
-				for _, lineBlame := range gitBlames {
-					if lineBlame.Commit != commit {
-						continue
-					}
-					if reportLine < 0 || lineBlame.Line < reportLine && lineBlame.Commit == commit {
-						reportLine = lineBlame.Line
-					}
-				}
-			}
-		}
-	}
-
+	reportLine := blameReportLine(report, summary, pb)
 	if reportLine < 0 {
-		return
+		if !report.External {
+			return
+		}
+		// annotations can be placed on any line, not only on modified ones
+		reportLine, _ = report.Problem.LineRange()
 	}
 
 	var severity, atype string
@@ -447,6 +447,57 @@ func TestBitBucketReporter(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			description: "reports external problems on unmodified lines",
+			gitCmd: func(args ...string) ([]byte, error) {
+				if args[0] == "rev-parse" {
+					return []byte("fake-commit-id"), nil
+				}
+				if args[0] == "blame" {
+					content := blameLine("fake-commit-00", 1, "bar.txt", "up")
+					return []byte(content), nil
+				}
+				return nil, nil
+			},
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "bar.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Fragment: "up",
+							Lines:    []int{1},
+							Reporter: "rule/removed",
+							Text:     "up recording rule was removed on this branch",
+							Severity: checks.Bug,
+						},
+						External: true,
+					},
+				},
+				FileChanges: discovery.NewFileCommitsFromMap(map[string][]string{"foo.txt": {"fake-commit-id"}}),
+			},
+			report: reporter.BitBucketReport{
+				Title:  "Pint - Prometheus rules linter",
+				Result: "FAIL",
+			},
+			annotations: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						Path:     "bar.txt",
+						Line:     1,
+						Message:  "rule/removed: up recording rule was removed on this branch",
+						Severity: "MEDIUM",
+						Type:     "BUG",
+					},
+				},
+			},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
 	}
 
 	for _, tc := range testCases {
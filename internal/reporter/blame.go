@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/git"
+)
+
+func blameReports(reports []Report, gitCmd git.CommandRunner) (pb git.FileBlames, err error) {
+	pb = make(git.FileBlames)
+	for _, report := range reports {
+		if _, ok := pb[report.Path]; ok {
+			continue
+		}
+		pb[report.Path], err = git.Blame(report.Path, gitCmd)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// blameReportLine returns the line number that given report should be attached to
+// when commenting on a pull request. It will be one of the problem lines that
+// was modified by a commit from scanned changes. Fatal problems are always
+// reported, on the first modified line of the file if needed.
+// Returns -1 if report shouldn't be attached to any line.
+func blameReportLine(report Report, summary Summary, pb git.FileBlames) int {
+	gitBlames, ok := pb[report.Path]
+	if !ok {
+		return -1
+	}
+
+	reportLine := -1
+	for _, pl := range report.Problem.Lines {
+		commit := gitBlames.GetCommit(pl)
+		if summary.FileChanges.HasCommit(commit) {
+			reportLine = pl
+		}
+	}
+
+	if reportLine < 0 && report.Problem.Severity == checks.Fatal {
+		for _, fl := range summary.FileChanges.Results() {
+			if fl.Path != report.Path {
+				continue
+			}
+			for _, commit := range fl.Commits {
+				for _, lineBlame := range gitBlames {
+					if lineBlame.Commit != commit {
+						continue
+					}
+					if reportLine < 0 || lineBlame.Line < reportLine && lineBlame.Commit == commit {
+						reportLine = lineBlame.Line
+					}
+				}
+			}
+		}
+	}
+
+	return reportLine
+}
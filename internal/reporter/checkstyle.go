@@ -0,0 +1,77 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type CheckstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+type CheckstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
+}
+
+type Checkstyle struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []CheckstyleFile `xml:"file"`
+}
+
+func NewCheckstyleReporter(output io.Writer) CheckstyleReporter {
+	return CheckstyleReporter{output: output}
+}
+
+// CheckstyleReporter writes reports as Checkstyle XML, with one file element
+// per path and one error element per problem
+type CheckstyleReporter struct {
+	output io.Writer
+}
+
+func (cr CheckstyleReporter) Submit(summary Summary) error {
+	cs := Checkstyle{Version: "4.3"}
+
+	for _, report := range sortReports(summary.Reports) {
+		if len(cs.Files) == 0 || cs.Files[len(cs.Files)-1].Name != report.Path {
+			cs.Files = append(cs.Files, CheckstyleFile{Name: report.Path})
+		}
+		firstLine, _ := report.Problem.LineRange()
+		f := &cs.Files[len(cs.Files)-1]
+		f.Errors = append(f.Errors, CheckstyleError{
+			Line:     firstLine,
+			Severity: checkstyleSeverity(report.Problem.Severity),
+			Message:  report.Problem.Text,
+			Source:   report.Problem.Reporter,
+		})
+	}
+
+	return writeXML(cr.output, cs)
+}
+
+func checkstyleSeverity(s checks.Severity) string {
+	switch s {
+	case checks.Fatal, checks.Bug:
+		return "error"
+	case checks.Warning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func writeXML(output io.Writer, v interface{}) error {
+	content, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(output, "%s%s\n", xml.Header, content)
+	return err
+}
@@ -0,0 +1,110 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckstyleReporter(t *testing.T) {
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		output      string
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- alert: errors
+  expr: sum(errors) by (job) > 0
+`))
+
+	testCases := []testCaseT{
+		{
+			description: "no reports",
+			summary:     reporter.Summary{},
+			output: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3"></checkstyle>
+`,
+		},
+		{
+			description: "multiple files",
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{4, 5},
+							Reporter: "mock",
+							Text:     "mock text 2",
+							Severity: checks.Warning,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{2, 3},
+							Reporter: "mock",
+							Text:     "mock <text>",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{5},
+							Reporter: "mock",
+							Text:     "mock info",
+							Severity: checks.Information,
+						},
+					},
+					{
+						Path: "bar.txt",
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "pint/parse",
+							Text:     "did not find expected key",
+							Severity: checks.Fatal,
+						},
+					},
+				},
+			},
+			output: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="bar.txt">
+    <error line="1" severity="error" message="did not find expected key" source="pint/parse"></error>
+  </file>
+  <file name="foo.txt">
+    <error line="2" severity="error" message="mock &lt;text&gt;" source="mock"></error>
+    <error line="4" severity="warning" message="mock text 2" source="mock"></error>
+    <error line="5" severity="info" message="mock info" source="mock"></error>
+  </file>
+</checkstyle>
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			r := reporter.NewCheckstyleReporter(out)
+			if err := r.Submit(tc.summary); err != nil {
+				t.Errorf("Submit() returned an error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tc.output, out.String()); diff != "" {
+				t.Errorf("Submit() wrote wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
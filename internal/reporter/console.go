@@ -21,29 +21,7 @@ type ConsoleReporter struct {
 }
 
 func (cr ConsoleReporter) Submit(summary Summary) error {
-	reports := summary.Reports
-	reps := reports[:]
-	sort.Slice(reps, func(i, j int) bool {
-		if reports[i].Path < reports[j].Path {
-			return true
-		}
-		if reports[i].Path > reports[j].Path {
-			return false
-		}
-		if reports[i].Problem.Lines[0] < reports[j].Problem.Lines[0] {
-			return true
-		}
-		if reports[i].Problem.Lines[0] > reports[j].Problem.Lines[0] {
-			return false
-		}
-		if reports[i].Problem.Reporter < reports[j].Problem.Reporter {
-			return true
-		}
-		if reports[i].Problem.Reporter > reports[j].Problem.Reporter {
-			return false
-		}
-		return reports[i].Problem.Text < reports[j].Problem.Text
-	})
+	reps := sortReports(summary.Reports)
 
 	perFile := map[string][]string{}
 	for _, report := range reps {
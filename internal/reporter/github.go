@@ -0,0 +1,239 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/git"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// githubMarker is added to every review and comment body so we can
+	// find content created by previous pint runs
+	githubMarker        = "<!-- pint -->"
+	githubPerPage       = 100
+	githubOutdatedTitle = "This review is outdated, see the most recent pint review for current results."
+)
+
+type GitHubReviewComment struct {
+	ID   int    `json:"id,omitempty"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+type GitHubReview struct {
+	ID       int                   `json:"id,omitempty"`
+	CommitID string                `json:"commit_id,omitempty"`
+	Body     string                `json:"body"`
+	Event    string                `json:"event,omitempty"`
+	Comments []GitHubReviewComment `json:"comments,omitempty"`
+}
+
+func NewGitHubReporter(uri string, timeout time.Duration, token, owner, repo string, prNum int, gitCmd git.CommandRunner) GitHubReporter {
+	return GitHubReporter{
+		uri:       strings.TrimSuffix(uri, "/"),
+		timeout:   timeout,
+		authToken: token,
+		owner:     owner,
+		repo:      repo,
+		prNum:     prNum,
+		gitCmd:    gitCmd,
+	}
+}
+
+// GitHubReporter sends linter results to GitHub as a pull request review using
+// https://docs.github.com/en/rest/reference/pulls#reviews
+type GitHubReporter struct {
+	uri       string
+	timeout   time.Duration
+	authToken string
+	owner     string
+	repo      string
+	prNum     int
+	gitCmd    git.CommandRunner
+}
+
+func (r GitHubReporter) Submit(summary Summary) (err error) {
+	headCommit, err := git.HeadCommit(r.gitCmd)
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	log.Info().Str("commit", headCommit).Msg("Got HEAD commit from git")
+
+	pb, err := blameReports(summary.Reports, r.gitCmd)
+	if err != nil {
+		return fmt.Errorf("failed to run git blame: %w", err)
+	}
+
+	comments := []GitHubReviewComment{}
+	// lines that weren't modified can't be commented on, so external reports
+	// on those lines are included in the review body instead
+	external := []string{}
+	var problems int
+	for _, report := range summary.Reports {
+		reportLine := blameReportLine(report, summary, pb)
+		switch {
+		case reportLine >= 0:
+			comments = append(comments, r.makeComment(report, reportLine))
+		case report.External:
+			firstLine, _ := report.Problem.LineRange()
+			external = append(external, fmt.Sprintf("- `%s:%d`: **%s**: %s (`%s`)", report.Path, firstLine, report.Problem.Severity, report.Problem.Text, report.Problem.Reporter))
+		default:
+			continue
+		}
+		if !report.IsPassing() {
+			problems++
+		}
+	}
+
+	// Remove results of previous runs first so we don't end up with stale
+	// comments if problems were fixed.
+	if err = r.cleanup(); err != nil {
+		return fmt.Errorf("failed to remove previous GitHub review: %w", err)
+	}
+
+	if len(comments) > 0 || len(external) > 0 {
+		if err = r.createReview(headCommit, problems, comments, external); err != nil {
+			return fmt.Errorf("failed to create GitHub review: %w", err)
+		}
+	}
+
+	if summary.HasFatalProblems() {
+		return fmt.Errorf("fatal error(s) reported")
+	}
+
+	return nil
+}
+
+func (r GitHubReporter) makeComment(report Report, line int) GitHubReviewComment {
+	return GitHubReviewComment{
+		Path: report.Path,
+		Line: line,
+		Side: "RIGHT",
+		Body: fmt.Sprintf("%s\n**%s**: %s (`%s`)", githubMarker, report.Problem.Severity, report.Problem.Text, report.Problem.Reporter),
+	}
+}
+
+func (r GitHubReporter) createReview(commit string, problems int, comments []GitHubReviewComment, external []string) error {
+	body := fmt.Sprintf("%s\nPint - Prometheus rules linter found %d problem(s) and %d comment(s).", githubMarker, problems, len(comments)+len(external)-problems)
+	if len(external) > 0 {
+		body += "\n\nProblems caused by changes on this branch, reported on lines that weren't modified:\n" + strings.Join(external, "\n")
+	}
+	payload, _ := json.Marshal(GitHubReview{
+		CommitID: commit,
+		Body:     body,
+		Event:    "COMMENT",
+		Comments: comments,
+	})
+	_, err := r.githubRequest(http.MethodPost, r.pullURL("reviews"), payload)
+	return err
+}
+
+func (r GitHubReporter) cleanup() error {
+	reviews := []GitHubReview{}
+	if err := r.listAll(r.pullURL("reviews"), &reviews); err != nil {
+		return err
+	}
+
+	for _, review := range reviews {
+		if !strings.HasPrefix(review.Body, githubMarker) {
+			continue
+		}
+		log.Debug().Int("id", review.ID).Msg("Found previous pint review")
+
+		comments := []GitHubReviewComment{}
+		if err := r.listAll(r.pullURL(fmt.Sprintf("reviews/%d/comments", review.ID)), &comments); err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if !strings.HasPrefix(comment.Body, githubMarker) {
+				continue
+			}
+			url := fmt.Sprintf("%s/repos/%s/%s/pulls/comments/%d", r.uri, r.owner, r.repo, comment.ID)
+			if _, err := r.githubRequest(http.MethodDelete, url, nil); err != nil {
+				return err
+			}
+		}
+
+		if strings.Contains(review.Body, githubOutdatedTitle) {
+			continue
+		}
+		payload, _ := json.Marshal(GitHubReview{Body: fmt.Sprintf("%s\n%s", githubMarker, githubOutdatedTitle)})
+		if _, err := r.githubRequest(http.MethodPut, r.pullURL(fmt.Sprintf("reviews/%d", review.ID)), payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r GitHubReporter) pullURL(suffix string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/pulls/%d/%s", r.uri, r.owner, r.repo, r.prNum, suffix)
+}
+
+// listAll will fetch all pages of a list response and decode them into dst,
+// which must be a pointer to a slice
+func (r GitHubReporter) listAll(url string, dst interface{}) error {
+	items := []json.RawMessage{}
+	for page := 1; ; page++ {
+		body, err := r.githubRequest(http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", url, githubPerPage, page), nil)
+		if err != nil {
+			return err
+		}
+		var pageItems []json.RawMessage
+		if err = json.Unmarshal(body, &pageItems); err != nil {
+			return fmt.Errorf("failed to decode GitHub response: %w", err)
+		}
+		items = append(items, pageItems...)
+		if len(pageItems) < githubPerPage {
+			break
+		}
+	}
+
+	content, _ := json.Marshal(items)
+	return json.Unmarshal(content, dst)
+}
+
+func (r GitHubReporter) githubRequest(method, url string, body []byte) ([]byte, error) {
+	log.Debug().Str("url", url).Str("method", method).Msg("Sending a request to GitHub")
+	log.Debug().Bytes("body", body).Msg("Request payload")
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", r.authToken))
+
+	var netClient = &http.Client{
+		Timeout: r.timeout,
+	}
+
+	resp, err := netClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Int("status", resp.StatusCode).Msg("GitHub request completed")
+	if resp.StatusCode >= 300 {
+		log.Error().Bytes("body", content).Str("url", url).Int("code", resp.StatusCode).Msg("Got a non 2xx response")
+		return nil, fmt.Errorf("%s request failed", method)
+	}
+
+	return content, nil
+}
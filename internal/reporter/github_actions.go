@@ -0,0 +1,57 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func NewGitHubActionsReporter(output io.Writer) GitHubActionsReporter {
+	return GitHubActionsReporter{output: output}
+}
+
+// GitHubActionsReporter prints reports as GitHub Actions workflow commands
+// https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+// which are rendered as annotations without the need for any API access
+type GitHubActionsReporter struct {
+	output io.Writer
+}
+
+func (gr GitHubActionsReporter) Submit(summary Summary) error {
+	for _, report := range sortReports(summary.Reports) {
+		firstLine, lastLine := report.Problem.LineRange()
+		_, err := fmt.Fprintf(gr.output, "::%s file=%s,line=%d,endLine=%d,title=%s::%s\n",
+			githubActionsCommand(report.Problem.Severity),
+			githubActionsEscapeProperty(report.Path),
+			firstLine,
+			lastLine,
+			githubActionsEscapeProperty(report.Problem.Reporter),
+			githubActionsEscapeData(report.Problem.Text),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func githubActionsCommand(s checks.Severity) string {
+	switch s {
+	case checks.Fatal, checks.Bug:
+		return "error"
+	case checks.Warning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func githubActionsEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func githubActionsEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,101 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGitHubActionsReporter(t *testing.T) {
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		output      string
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- alert: errors
+  expr: sum(errors) by (job) > 0
+`))
+
+	testCases := []testCaseT{
+		{
+			description: "no reports",
+			summary:     reporter.Summary{},
+			output:      "",
+		},
+		{
+			description: "multiple files",
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{4, 5},
+							Reporter: "mock",
+							Text:     "mock text 2",
+							Severity: checks.Warning,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{2, 3},
+							Reporter: "mock",
+							Text:     "100% broken\nsecond line",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{5},
+							Reporter: "mock",
+							Text:     "mock info",
+							Severity: checks.Information,
+						},
+					},
+					{
+						Path: "bar,1.txt",
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "pint/parse",
+							Text:     "did not find expected key",
+							Severity: checks.Fatal,
+						},
+					},
+				},
+			},
+			output: `::error file=bar%2C1.txt,line=1,endLine=1,title=pint/parse::did not find expected key
+::error file=foo.txt,line=2,endLine=3,title=mock::100%25 broken%0Asecond line
+::warning file=foo.txt,line=4,endLine=5,title=mock::mock text 2
+::notice file=foo.txt,line=5,endLine=5,title=mock::mock info
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			r := reporter.NewGitHubActionsReporter(out)
+			if err := r.Submit(tc.summary); err != nil {
+				t.Errorf("Submit() returned an error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tc.output, out.String()); diff != "" {
+				t.Errorf("Submit() wrote wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package reporter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestGitHubReporter(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	type errorCheck func(err error) error
+
+	type testCaseT struct {
+		description  string
+		gitCmd       git.CommandRunner
+		summary      reporter.Summary
+		reviews      string
+		httpStatus   int
+		requests     []string
+		review       *reporter.GitHubReview
+		errorHandler errorCheck
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- record: sum errors
+  expr: sum(errors) by (job)
+`))
+
+	gitCmd := func(args ...string) ([]byte, error) {
+		if args[0] == "rev-parse" {
+			return []byte("fake-commit-id"), nil
+		}
+		if args[0] == "blame" {
+			content := blameLine("fake-commit-00", 1, "foo.txt", "ignore") +
+				blameLine("fake-commit-id", 2, "foo.txt", "up == 0") +
+				blameLine("fake-commit-id", 4, "foo.txt", "errors")
+			return []byte(content), nil
+		}
+		return nil, nil
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "returns an error on git head failure",
+			gitCmd: func(args ...string) ([]byte, error) {
+				return nil, errors.New("git head error")
+			},
+			errorHandler: func(err error) error {
+				if err != nil && err.Error() == "failed to get HEAD commit: git head error" {
+					return nil
+				}
+				return fmt.Errorf("Expected git head error, got %v", err)
+			},
+		},
+		{
+			description: "returns an error on non-200 HTTP response",
+			gitCmd:      gitCmd,
+			httpStatus:  http.StatusUnauthorized,
+			requests:    []string{"GET /repos/owner/repo/pulls/123/reviews"},
+			errorHandler: func(err error) error {
+				if err != nil && err.Error() == "failed to remove previous GitHub review: GET request failed" {
+					return nil
+				}
+				return fmt.Errorf("Expected 'GET request failed', got %q", err)
+			},
+		},
+		{
+			description: "doesn't create a review when there are no problems",
+			gitCmd:      gitCmd,
+			summary:     reporter.Summary{},
+			reviews:     "[]",
+			requests:    []string{"GET /repos/owner/repo/pulls/123/reviews"},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			description: "cleans up previous review and sends a new one",
+			gitCmd:      gitCmd,
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "mock",
+							Text:     "this should be ignored, line is not part of the diff",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{2},
+							Reporter: "mock",
+							Text:     "mock text",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{3, 4},
+							Reporter: "mock",
+							Text:     "mock text 2",
+							Severity: checks.Warning,
+						},
+					},
+				},
+				FileChanges: discovery.NewFileCommitsFromMap(map[string][]string{"foo.txt": {"fake-commit-id"}}),
+			},
+			reviews: `[
+				{"id": 1, "body": "<!-- pint -->\nPint - Prometheus rules linter found 1 problem(s) and 0 comment(s)."},
+				{"id": 2, "body": "LGTM"},
+				{"id": 3, "body": "<!-- pint -->\nThis review is outdated, see the most recent pint review for current results."}
+			]`,
+			requests: []string{
+				"GET /repos/owner/repo/pulls/123/reviews",
+				"GET /repos/owner/repo/pulls/123/reviews/1/comments",
+				"DELETE /repos/owner/repo/pulls/comments/11",
+				"PUT /repos/owner/repo/pulls/123/reviews/1",
+				"GET /repos/owner/repo/pulls/123/reviews/3/comments",
+				"POST /repos/owner/repo/pulls/123/reviews",
+			},
+			review: &reporter.GitHubReview{
+				CommitID: "fake-commit-id",
+				Body:     "<!-- pint -->\nPint - Prometheus rules linter found 1 problem(s) and 1 comment(s).",
+				Event:    "COMMENT",
+				Comments: []reporter.GitHubReviewComment{
+					{
+						Path: "foo.txt",
+						Line: 2,
+						Side: "RIGHT",
+						Body: "<!-- pint -->\n**Bug**: mock text (`mock`)",
+					},
+					{
+						Path: "foo.txt",
+						Line: 4,
+						Side: "RIGHT",
+						Body: "<!-- pint -->\n**Warning**: mock text 2 (`mock`)",
+					},
+				},
+			},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			description: "includes external reports on unmodified lines in review body",
+			gitCmd:      gitCmd,
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "mock",
+							Text:     "this should be ignored, line is not part of the diff",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "rule/removed",
+							Text:     "foo recording rule was removed on this branch",
+							Severity: checks.Bug,
+						},
+						External: true,
+					},
+				},
+				FileChanges: discovery.NewFileCommitsFromMap(map[string][]string{"foo.txt": {"fake-commit-id"}}),
+			},
+			reviews: "[]",
+			requests: []string{
+				"GET /repos/owner/repo/pulls/123/reviews",
+				"POST /repos/owner/repo/pulls/123/reviews",
+			},
+			review: &reporter.GitHubReview{
+				CommitID: "fake-commit-id",
+				Body:     "<!-- pint -->\nPint - Prometheus rules linter found 1 problem(s) and 0 comment(s).\n\nProblems caused by changes on this branch, reported on lines that weren't modified:\n- `foo.txt:1`: **Bug**: foo recording rule was removed on this branch (`rule/removed`)",
+				Event:    "COMMENT",
+			},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var lock sync.Mutex
+			requests := []string{}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+
+				lock.Lock()
+				requests = append(requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+				lock.Unlock()
+
+				if r.Header.Get("Authorization") != "token secret" {
+					t.Errorf("Got invalid Authorization header: %q", r.Header.Get("Authorization"))
+				}
+
+				if tc.httpStatus != 0 {
+					w.WriteHeader(tc.httpStatus)
+					_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+					return
+				}
+
+				switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+				case "GET /repos/owner/repo/pulls/123/reviews":
+					_, _ = w.Write([]byte(tc.reviews))
+				case "GET /repos/owner/repo/pulls/123/reviews/1/comments":
+					_, _ = w.Write([]byte(`[{"id": 11, "body": "<!-- pint -->\nmock"}, {"id": 12, "body": "human comment"}]`))
+				case "GET /repos/owner/repo/pulls/123/reviews/3/comments":
+					_, _ = w.Write([]byte(`[]`))
+				case "DELETE /repos/owner/repo/pulls/comments/11":
+					w.WriteHeader(http.StatusNoContent)
+				case "PUT /repos/owner/repo/pulls/123/reviews/1":
+					var review reporter.GitHubReview
+					if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+						t.Errorf("JSON decode error: %v", err)
+					}
+					if review.Body != "<!-- pint -->\nThis review is outdated, see the most recent pint review for current results." {
+						t.Errorf("Got wrong review update body: %q", review.Body)
+					}
+					_, _ = w.Write([]byte(`{}`))
+				case "POST /repos/owner/repo/pulls/123/reviews":
+					var review reporter.GitHubReview
+					if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+						t.Errorf("JSON decode error: %v", err)
+					}
+					if diff := cmp.Diff(tc.review, &review); diff != "" {
+						t.Errorf("Got wrong GitHub review (-want +got):\n%s", diff)
+					}
+					_, _ = w.Write([]byte(`{}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					t.Errorf("Unhandled request: %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer srv.Close()
+
+			r := reporter.NewGitHubReporter(srv.URL, time.Second, "secret", "owner", "repo", 123, tc.gitCmd)
+			err := r.Submit(tc.summary)
+			if e := tc.errorHandler(err); e != nil {
+				t.Errorf("error check failure: %s", e)
+				return
+			}
+
+			if tc.requests != nil {
+				if diff := cmp.Diff(tc.requests, requests); diff != "" {
+					t.Errorf("Wrong list of requests sent to GitHub (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
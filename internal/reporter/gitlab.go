@@ -0,0 +1,315 @@
+package reporter
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/git"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// gitlabMarker is added to every note body so we can find notes created
+	// by previous pint runs
+	gitlabMarker  = "<!-- pint -->"
+	gitlabPerPage = 100
+)
+
+type GitLabCodeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+type GitLabCodeQualityLocation struct {
+	Path  string                 `json:"path"`
+	Lines GitLabCodeQualityLines `json:"lines"`
+}
+
+type GitLabCodeQualityIssue struct {
+	Description string                    `json:"description"`
+	CheckName   string                    `json:"check_name"`
+	Fingerprint string                    `json:"fingerprint"`
+	Severity    string                    `json:"severity"`
+	Location    GitLabCodeQualityLocation `json:"location"`
+}
+
+type GitLabDiffRefs struct {
+	BaseSha  string `json:"base_sha"`
+	HeadSha  string `json:"head_sha"`
+	StartSha string `json:"start_sha"`
+}
+
+type GitLabMergeRequest struct {
+	DiffRefs GitLabDiffRefs `json:"diff_refs"`
+}
+
+type GitLabNote struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+type GitLabDiscussion struct {
+	ID    string       `json:"id"`
+	Notes []GitLabNote `json:"notes"`
+}
+
+type GitLabPosition struct {
+	BaseSha      string `json:"base_sha"`
+	HeadSha      string `json:"head_sha"`
+	StartSha     string `json:"start_sha"`
+	PositionType string `json:"position_type"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+}
+
+type GitLabNewDiscussion struct {
+	Body     string          `json:"body"`
+	Position *GitLabPosition `json:"position,omitempty"`
+}
+
+func NewGitLabReporter(codeQuality, uri string, timeout time.Duration, token, project string, mrIID int, gitCmd git.CommandRunner) GitLabReporter {
+	return GitLabReporter{
+		codeQuality: codeQuality,
+		uri:         strings.TrimSuffix(uri, "/"),
+		timeout:     timeout,
+		authToken:   token,
+		project:     project,
+		mrIID:       mrIID,
+		gitCmd:      gitCmd,
+	}
+}
+
+// GitLabReporter writes linter results as a GitLab Code Quality report
+// https://docs.gitlab.com/ee/user/project/merge_requests/code_quality.html
+// and, if uri is set, posts merge request discussions for problems on
+// modified lines using https://docs.gitlab.com/ee/api/discussions.html
+type GitLabReporter struct {
+	codeQuality string
+	uri         string
+	timeout     time.Duration
+	authToken   string
+	project     string
+	mrIID       int
+	gitCmd      git.CommandRunner
+}
+
+func (r GitLabReporter) Submit(summary Summary) (err error) {
+	if r.codeQuality != "" {
+		if err = r.writeCodeQuality(summary); err != nil {
+			return fmt.Errorf("failed to write GitLab Code Quality report: %w", err)
+		}
+	}
+
+	if r.uri != "" {
+		if err = r.postDiscussions(summary); err != nil {
+			return err
+		}
+	}
+
+	if summary.HasFatalProblems() {
+		return fmt.Errorf("fatal error(s) reported")
+	}
+
+	return nil
+}
+
+func (r GitLabReporter) writeCodeQuality(summary Summary) error {
+	issues := []GitLabCodeQualityIssue{}
+	seen := map[string]int{}
+	for _, report := range sortReports(summary.Reports) {
+		fingerprint := report.Fingerprint()
+		// GitLab requires fingerprints to be unique
+		if n, ok := seen[fingerprint]; ok {
+			seen[fingerprint]++
+			fingerprint = fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%s\n%d", fingerprint, n))))
+		} else {
+			seen[fingerprint] = 1
+		}
+
+		firstLine, lastLine := report.Problem.LineRange()
+		issues = append(issues, GitLabCodeQualityIssue{
+			Description: report.Problem.Text,
+			CheckName:   report.Problem.Reporter,
+			Fingerprint: fingerprint,
+			Severity:    gitlabSeverity(report.Problem.Severity),
+			Location: GitLabCodeQualityLocation{
+				Path:  report.Path,
+				Lines: GitLabCodeQualityLines{Begin: firstLine, End: lastLine},
+			},
+		})
+	}
+
+	content, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	log.Info().Str("path", r.codeQuality).Int("issues", len(issues)).Msg("Writing GitLab Code Quality report")
+	return os.WriteFile(r.codeQuality, content, 0644)
+}
+
+func (r GitLabReporter) postDiscussions(summary Summary) error {
+	headCommit, err := git.HeadCommit(r.gitCmd)
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	log.Info().Str("commit", headCommit).Msg("Got HEAD commit from git")
+
+	pb, err := blameReports(summary.Reports, r.gitCmd)
+	if err != nil {
+		return fmt.Errorf("failed to run git blame: %w", err)
+	}
+
+	body, err := r.gitlabRequest(http.MethodGet, r.mergeRequestURL(""), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get GitLab merge request details: %w", err)
+	}
+	var mr GitLabMergeRequest
+	if err = json.Unmarshal(body, &mr); err != nil {
+		return fmt.Errorf("failed to decode GitLab merge request details: %w", err)
+	}
+	// diff_refs are only set for the latest merge request version, but
+	// if HEAD commit is different it can still be used to post our comments
+	if mr.DiffRefs.HeadSha != headCommit {
+		log.Warn().Str("head", mr.DiffRefs.HeadSha).Str("commit", headCommit).Msg("Merge request HEAD commit doesn't match git HEAD")
+	}
+
+	// Remove notes from previous runs first so we don't end up with stale
+	// data if problems were fixed.
+	if err = r.deleteStaleNotes(); err != nil {
+		return fmt.Errorf("failed to remove previous GitLab notes: %w", err)
+	}
+
+	for _, report := range summary.Reports {
+		d := GitLabNewDiscussion{
+			Body: fmt.Sprintf("%s\n**%s**: %s (`%s`)", gitlabMarker, report.Problem.Severity, report.Problem.Text, report.Problem.Reporter),
+		}
+		reportLine := blameReportLine(report, summary, pb)
+		switch {
+		case reportLine >= 0:
+			d.Position = &GitLabPosition{
+				BaseSha:      mr.DiffRefs.BaseSha,
+				HeadSha:      mr.DiffRefs.HeadSha,
+				StartSha:     mr.DiffRefs.StartSha,
+				PositionType: "text",
+				NewPath:      report.Path,
+				NewLine:      reportLine,
+			}
+		case report.External:
+			// lines that weren't modified can't be commented on, so this
+			// is posted as a merge request note instead
+			firstLine, _ := report.Problem.LineRange()
+			d.Body = fmt.Sprintf("%s\n`%s:%d`: **%s**: %s (`%s`)", gitlabMarker, report.Path, firstLine, report.Problem.Severity, report.Problem.Text, report.Problem.Reporter)
+		default:
+			continue
+		}
+		payload, _ := json.Marshal(d)
+		if _, err = r.gitlabRequest(http.MethodPost, r.mergeRequestURL("/discussions"), payload); err != nil {
+			return fmt.Errorf("failed to create GitLab discussion: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r GitLabReporter) deleteStaleNotes() error {
+	// All pages are fetched before deleting anything, deleting the last note
+	// of a discussion removes it and that would shift all following pages.
+	type staleNote struct {
+		discussion string
+		note       int
+	}
+	stale := []staleNote{}
+	for page := 1; ; page++ {
+		body, err := r.gitlabRequest(http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", r.mergeRequestURL("/discussions"), gitlabPerPage, page), nil)
+		if err != nil {
+			return err
+		}
+
+		var discussions []GitLabDiscussion
+		if err = json.Unmarshal(body, &discussions); err != nil {
+			return fmt.Errorf("failed to decode GitLab response: %w", err)
+		}
+
+		for _, discussion := range discussions {
+			for _, note := range discussion.Notes {
+				if strings.HasPrefix(note.Body, gitlabMarker) {
+					stale = append(stale, staleNote{discussion: discussion.ID, note: note.ID})
+				}
+			}
+		}
+
+		if len(discussions) < gitlabPerPage {
+			break
+		}
+	}
+
+	for _, sn := range stale {
+		log.Debug().Str("discussion", sn.discussion).Int("note", sn.note).Msg("Deleting stale GitLab note")
+		u := r.mergeRequestURL(fmt.Sprintf("/discussions/%s/notes/%d", sn.discussion, sn.note))
+		if _, err := r.gitlabRequest(http.MethodDelete, u, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GitLabReporter) mergeRequestURL(suffix string) string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d%s", r.uri, url.PathEscape(r.project), r.mrIID, suffix)
+}
+
+func (r GitLabReporter) gitlabRequest(method, url string, body []byte) ([]byte, error) {
+	log.Debug().Str("url", url).Str("method", method).Msg("Sending a request to GitLab")
+	log.Debug().Bytes("body", body).Msg("Request payload")
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", r.authToken)
+
+	var netClient = &http.Client{
+		Timeout: r.timeout,
+	}
+
+	resp, err := netClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug().Int("status", resp.StatusCode).Msg("GitLab request completed")
+	if resp.StatusCode >= 300 {
+		log.Error().Bytes("body", content).Str("url", url).Int("code", resp.StatusCode).Msg("Got a non 2xx response")
+		return nil, fmt.Errorf("%s request failed", method)
+	}
+
+	return content, nil
+}
+
+func gitlabSeverity(s checks.Severity) string {
+	switch s {
+	case checks.Fatal:
+		return "blocker"
+	case checks.Bug:
+		return "major"
+	case checks.Warning:
+		return "minor"
+	default:
+		return "info"
+	}
+}
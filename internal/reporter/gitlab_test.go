@@ -0,0 +1,433 @@
+package reporter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rs/zerolog"
+)
+
+func TestGitLabReporter(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	type errorCheck func(err error) error
+
+	type testCaseT struct {
+		description  string
+		gitCmd       git.CommandRunner
+		summary      reporter.Summary
+		noURI        bool
+		httpStatus   int
+		requests     []string
+		issues       []reporter.GitLabCodeQualityIssue
+		discussions  []reporter.GitLabNewDiscussion
+		errorHandler errorCheck
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- record: sum errors
+  expr: sum(errors) by (job)
+`))
+
+	gitCmd := func(args ...string) ([]byte, error) {
+		if args[0] == "rev-parse" {
+			return []byte("fake-commit-id"), nil
+		}
+		if args[0] == "blame" {
+			content := blameLine("fake-commit-00", 1, "foo.txt", "ignore") +
+				blameLine("fake-commit-id", 2, "foo.txt", "up == 0") +
+				blameLine("fake-commit-id", 4, "foo.txt", "errors")
+			return []byte(content), nil
+		}
+		return nil, nil
+	}
+
+	summary := reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path: "foo.txt",
+				Rule: mockRules[1],
+				Problem: checks.Problem{
+					Lines:    []int{1},
+					Reporter: "mock",
+					Text:     "this should be ignored, line is not part of the diff",
+					Severity: checks.Bug,
+				},
+			},
+			{
+				Path: "foo.txt",
+				Rule: mockRules[0],
+				Problem: checks.Problem{
+					Lines:    []int{2},
+					Reporter: "mock",
+					Text:     "mock text",
+					Severity: checks.Bug,
+				},
+			},
+			{
+				Path: "foo.txt",
+				Rule: mockRules[1],
+				Problem: checks.Problem{
+					Lines:    []int{3, 4},
+					Reporter: "mock",
+					Text:     "mock text 2",
+					Severity: checks.Warning,
+				},
+			},
+		},
+		FileChanges: discovery.NewFileCommitsFromMap(map[string][]string{"foo.txt": {"fake-commit-id"}}),
+	}
+
+	issues := []reporter.GitLabCodeQualityIssue{
+		{
+			Description: "this should be ignored, line is not part of the diff",
+			CheckName:   "mock",
+			Severity:    "major",
+			Location: reporter.GitLabCodeQualityLocation{
+				Path:  "foo.txt",
+				Lines: reporter.GitLabCodeQualityLines{Begin: 1, End: 1},
+			},
+		},
+		{
+			Description: "mock text",
+			CheckName:   "mock",
+			Severity:    "major",
+			Location: reporter.GitLabCodeQualityLocation{
+				Path:  "foo.txt",
+				Lines: reporter.GitLabCodeQualityLines{Begin: 2, End: 2},
+			},
+		},
+		{
+			Description: "mock text 2",
+			CheckName:   "mock",
+			Severity:    "minor",
+			Location: reporter.GitLabCodeQualityLocation{
+				Path:  "foo.txt",
+				Lines: reporter.GitLabCodeQualityLines{Begin: 3, End: 4},
+			},
+		},
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "only writes Code Quality report when uri is not set",
+			gitCmd: func(args ...string) ([]byte, error) {
+				return nil, errors.New("git shouldn't be called")
+			},
+			summary:  summary,
+			noURI:    true,
+			requests: []string{},
+			issues:   issues,
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			description: "returns an error on git head failure",
+			gitCmd: func(args ...string) ([]byte, error) {
+				return nil, errors.New("git head error")
+			},
+			issues: []reporter.GitLabCodeQualityIssue{},
+			errorHandler: func(err error) error {
+				if err != nil && err.Error() == "failed to get HEAD commit: git head error" {
+					return nil
+				}
+				return fmt.Errorf("Expected git head error, got %v", err)
+			},
+		},
+		{
+			description: "returns an error on non-200 HTTP response",
+			gitCmd:      gitCmd,
+			httpStatus:  http.StatusUnauthorized,
+			requests:    []string{"GET /api/v4/projects/1234/merge_requests/5"},
+			issues:      []reporter.GitLabCodeQualityIssue{},
+			errorHandler: func(err error) error {
+				if err != nil && err.Error() == "failed to get GitLab merge request details: GET request failed" {
+					return nil
+				}
+				return fmt.Errorf("Expected 'GET request failed', got %q", err)
+			},
+		},
+		{
+			description: "deletes stale notes and posts new discussions",
+			gitCmd:      gitCmd,
+			summary:     summary,
+			requests: []string{
+				"GET /api/v4/projects/1234/merge_requests/5",
+				"GET /api/v4/projects/1234/merge_requests/5/discussions",
+				"DELETE /api/v4/projects/1234/merge_requests/5/discussions/abc/notes/11",
+				"POST /api/v4/projects/1234/merge_requests/5/discussions",
+				"POST /api/v4/projects/1234/merge_requests/5/discussions",
+			},
+			issues: issues,
+			discussions: []reporter.GitLabNewDiscussion{
+				{
+					Body: "<!-- pint -->\n**Bug**: mock text (`mock`)",
+					Position: &reporter.GitLabPosition{
+						BaseSha:      "base",
+						HeadSha:      "fake-commit-id",
+						StartSha:     "start",
+						PositionType: "text",
+						NewPath:      "foo.txt",
+						NewLine:      2,
+					},
+				},
+				{
+					Body: "<!-- pint -->\n**Warning**: mock text 2 (`mock`)",
+					Position: &reporter.GitLabPosition{
+						BaseSha:      "base",
+						HeadSha:      "fake-commit-id",
+						StartSha:     "start",
+						PositionType: "text",
+						NewPath:      "foo.txt",
+						NewLine:      4,
+					},
+				},
+			},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			description: "posts external reports on unmodified lines as merge request notes",
+			gitCmd:      gitCmd,
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "rule/removed",
+							Text:     "foo recording rule was removed on this branch",
+							Severity: checks.Bug,
+						},
+						External: true,
+					},
+				},
+				FileChanges: discovery.NewFileCommitsFromMap(map[string][]string{"foo.txt": {"fake-commit-id"}}),
+			},
+			requests: []string{
+				"GET /api/v4/projects/1234/merge_requests/5",
+				"GET /api/v4/projects/1234/merge_requests/5/discussions",
+				"DELETE /api/v4/projects/1234/merge_requests/5/discussions/abc/notes/11",
+				"POST /api/v4/projects/1234/merge_requests/5/discussions",
+			},
+			issues: []reporter.GitLabCodeQualityIssue{
+				{
+					Description: "foo recording rule was removed on this branch",
+					CheckName:   "rule/removed",
+					Severity:    "major",
+					Location: reporter.GitLabCodeQualityLocation{
+						Path:  "foo.txt",
+						Lines: reporter.GitLabCodeQualityLines{Begin: 1, End: 1},
+					},
+				},
+			},
+			discussions: []reporter.GitLabNewDiscussion{
+				{
+					Body: "<!-- pint -->\n`foo.txt:1`: **Bug**: foo recording rule was removed on this branch (`rule/removed`)",
+				},
+			},
+			errorHandler: func(err error) error {
+				if err != nil {
+					return fmt.Errorf("Unpexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var lock sync.Mutex
+			requests := []string{}
+			discussions := []reporter.GitLabNewDiscussion{}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+
+				lock.Lock()
+				requests = append(requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+				lock.Unlock()
+
+				if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+					t.Errorf("Got invalid PRIVATE-TOKEN header: %q", r.Header.Get("PRIVATE-TOKEN"))
+				}
+
+				if tc.httpStatus != 0 {
+					w.WriteHeader(tc.httpStatus)
+					_, _ = w.Write([]byte(`{"message": "401 Unauthorized"}`))
+					return
+				}
+
+				switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+				case "GET /api/v4/projects/1234/merge_requests/5":
+					_, _ = w.Write([]byte(`{"diff_refs": {"base_sha": "base", "head_sha": "fake-commit-id", "start_sha": "start"}}`))
+				case "GET /api/v4/projects/1234/merge_requests/5/discussions":
+					_, _ = w.Write([]byte(`[
+						{"id": "abc", "notes": [{"id": 11, "body": "<!-- pint -->\nmock"}]},
+						{"id": "def", "notes": [{"id": 12, "body": "human comment"}]}
+					]`))
+				case "DELETE /api/v4/projects/1234/merge_requests/5/discussions/abc/notes/11":
+					w.WriteHeader(http.StatusNoContent)
+				case "POST /api/v4/projects/1234/merge_requests/5/discussions":
+					var d reporter.GitLabNewDiscussion
+					if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+						t.Errorf("JSON decode error: %v", err)
+					}
+					lock.Lock()
+					discussions = append(discussions, d)
+					lock.Unlock()
+					_, _ = w.Write([]byte(`{}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					t.Errorf("Unhandled request: %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer srv.Close()
+
+			uri := srv.URL
+			if tc.noURI {
+				uri = ""
+			}
+			codeQuality := path.Join(t.TempDir(), "gl-code-quality-report.json")
+			r := reporter.NewGitLabReporter(codeQuality, uri, time.Second, "secret", "1234", 5, tc.gitCmd)
+			err := r.Submit(tc.summary)
+			if e := tc.errorHandler(err); e != nil {
+				t.Errorf("error check failure: %s", e)
+				return
+			}
+
+			if tc.requests != nil {
+				if diff := cmp.Diff(tc.requests, requests); diff != "" {
+					t.Errorf("Wrong list of requests sent to GitLab (-want +got):\n%s", diff)
+				}
+			}
+
+			if tc.discussions != nil {
+				if diff := cmp.Diff(tc.discussions, discussions); diff != "" {
+					t.Errorf("Wrong discussions sent to GitLab (-want +got):\n%s", diff)
+				}
+			}
+
+			content, err := os.ReadFile(codeQuality)
+			if err != nil {
+				t.Fatalf("Failed to read Code Quality report: %s", err)
+			}
+			var got []reporter.GitLabCodeQualityIssue
+			if err = json.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Failed to decode Code Quality report: %s", err)
+			}
+			fingerprints := map[string]struct{}{}
+			for _, issue := range got {
+				if _, ok := fingerprints[issue.Fingerprint]; ok || issue.Fingerprint == "" {
+					t.Errorf("Invalid or duplicated fingerprint: %q", issue.Fingerprint)
+				}
+				fingerprints[issue.Fingerprint] = struct{}{}
+			}
+			if diff := cmp.Diff(tc.issues, got, cmpopts.IgnoreFields(reporter.GitLabCodeQualityIssue{}, "Fingerprint")); diff != "" {
+				t.Errorf("Wrong Code Quality report (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGitLabReporterDeletesAllStaleNotes(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	// GitLab removes a discussion once its last note is deleted, so pages
+	// shift while we delete notes
+	var lock sync.Mutex
+	discussions := []reporter.GitLabDiscussion{}
+	for i := 1; i <= 250; i++ {
+		body := "<!-- pint -->\nmock"
+		if i%10 == 0 {
+			body = "human comment"
+		}
+		discussions = append(discussions, reporter.GitLabDiscussion{
+			ID:    fmt.Sprintf("d%d", i),
+			Notes: []reporter.GitLabNote{{ID: i, Body: body}},
+		})
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1234/merge_requests/5":
+			_, _ = w.Write([]byte(`{"diff_refs": {"base_sha": "base", "head_sha": "fake-commit-id", "start_sha": "start"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/1234/merge_requests/5/discussions":
+			var perPage, page int
+			_, _ = fmt.Sscan(r.URL.Query().Get("per_page"), &perPage)
+			_, _ = fmt.Sscan(r.URL.Query().Get("page"), &page)
+			start, end := (page-1)*perPage, page*perPage
+			if start > len(discussions) {
+				start = len(discussions)
+			}
+			if end > len(discussions) {
+				end = len(discussions)
+			}
+			_ = json.NewEncoder(w).Encode(discussions[start:end])
+		case r.Method == http.MethodDelete:
+			for i, d := range discussions {
+				if fmt.Sprintf("/api/v4/projects/1234/merge_requests/5/discussions/%s/notes/%d", d.ID, d.Notes[0].ID) == r.URL.Path {
+					discussions = append(discussions[:i], discussions[i+1:]...)
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+			t.Errorf("Unknown note: %s", r.URL.Path)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			t.Errorf("Unhandled request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	gitCmd := func(args ...string) ([]byte, error) {
+		return []byte("fake-commit-id"), nil
+	}
+	codeQuality := path.Join(t.TempDir(), "gl-code-quality-report.json")
+	r := reporter.NewGitLabReporter(codeQuality, srv.URL, time.Second, "secret", "1234", 5, gitCmd)
+	if err := r.Submit(reporter.Summary{}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(discussions) != 25 {
+		t.Errorf("Expected 25 discussions left, got %d", len(discussions))
+	}
+	for _, d := range discussions {
+		if d.Notes[0].Body != "human comment" {
+			t.Errorf("Stale note wasn't deleted: %s", d.ID)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type JSONReport struct {
+	Path     string `json:"path"`
+	Name     string `json:"name,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Fragment string `json:"fragment,omitempty"`
+	Lines    []int  `json:"lines"`
+	Reporter string `json:"reporter"`
+	Text     string `json:"text"`
+	Severity string `json:"severity"`
+}
+
+type JSONSummary struct {
+	Reports  []JSONReport   `json:"reports"`
+	Problems map[string]int `json:"problems"`
+}
+
+func NewJSONReporter(output io.Writer) JSONReporter {
+	return JSONReporter{output: output}
+}
+
+// JSONReporter writes all reports and a per severity summary as a single JSON document
+type JSONReporter struct {
+	output io.Writer
+}
+
+func (jr JSONReporter) Submit(summary Summary) error {
+	js := JSONSummary{
+		Reports:  []JSONReport{},
+		Problems: map[string]int{},
+	}
+
+	for _, report := range sortReports(summary.Reports) {
+		js.Reports = append(js.Reports, JSONReport{
+			Path:     report.Path,
+			Name:     report.Rule.Name(),
+			Kind:     ruleKind(report.Rule),
+			Fragment: report.Problem.Fragment,
+			Lines:    report.Problem.Lines,
+			Reporter: report.Problem.Reporter,
+			Text:     report.Problem.Text,
+			Severity: report.Problem.Severity.String(),
+		})
+	}
+
+	for s, c := range summary.CountBySeverity() {
+		js.Problems[s.String()] = c
+	}
+
+	enc := json.NewEncoder(jr.output)
+	enc.SetIndent("", "  ")
+	return enc.Encode(js)
+}
@@ -0,0 +1,136 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONReporter(t *testing.T) {
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		output      string
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- alert: errors
+  expr: sum(errors) by (job) > 0
+`))
+
+	testCases := []testCaseT{
+		{
+			description: "no reports",
+			summary:     reporter.Summary{},
+			output: `{
+  "reports": [],
+  "problems": {}
+}
+`,
+		},
+		{
+			description: "sorted reports",
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Fragment: "errors",
+							Lines:    []int{5},
+							Reporter: "mock",
+							Text:     "mock text 2",
+							Severity: checks.Warning,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Fragment: "up == 0",
+							Lines:    []int{2, 3},
+							Reporter: "mock",
+							Text:     "mock text",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "bar.txt",
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "pint/parse",
+							Text:     "did not find expected key",
+							Severity: checks.Fatal,
+						},
+					},
+				},
+			},
+			output: `{
+  "reports": [
+    {
+      "path": "bar.txt",
+      "lines": [
+        1
+      ],
+      "reporter": "pint/parse",
+      "text": "did not find expected key",
+      "severity": "Fatal"
+    },
+    {
+      "path": "foo.txt",
+      "name": "target is down",
+      "kind": "recording",
+      "fragment": "up == 0",
+      "lines": [
+        2,
+        3
+      ],
+      "reporter": "mock",
+      "text": "mock text",
+      "severity": "Bug"
+    },
+    {
+      "path": "foo.txt",
+      "name": "errors",
+      "kind": "alerting",
+      "fragment": "errors",
+      "lines": [
+        5
+      ],
+      "reporter": "mock",
+      "text": "mock text 2",
+      "severity": "Warning"
+    }
+  ],
+  "problems": {
+    "Bug": 1,
+    "Fatal": 1,
+    "Warning": 1
+  }
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			r := reporter.NewJSONReporter(out)
+			if err := r.Submit(tc.summary); err != nil {
+				t.Errorf("Submit() returned an error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tc.output, out.String()); diff != "" {
+				t.Errorf("Submit() wrote wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
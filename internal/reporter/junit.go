@@ -0,0 +1,159 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+type JUnitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+func NewJUnitReporter(output io.Writer) JUnitReporter {
+	return JUnitReporter{output: output}
+}
+
+// JUnitReporter writes reports as JUnit XML, every checked rule is a test case
+// that fails if there are any Bug or Fatal problems reported for it
+type JUnitReporter struct {
+	output io.Writer
+}
+
+type junitCase struct {
+	path     string
+	line     int
+	name     string
+	problems []checks.Problem
+}
+
+func (jr JUnitReporter) Submit(summary Summary) error {
+	cases := map[string]*junitCase{}
+	for _, entry := range summary.Entries {
+		key := junitKey(entry.Path, entry.Rule)
+		if _, ok := cases[key]; ok {
+			continue
+		}
+		cases[key] = &junitCase{
+			path: entry.Path,
+			line: firstLine(entry.Rule.Lines()),
+			name: junitCaseName(entry.Rule),
+		}
+	}
+
+	for _, report := range sortReports(summary.Reports) {
+		key := junitKey(report.Path, report.Rule)
+		if _, ok := cases[key]; !ok {
+			name := junitCaseName(report.Rule)
+			if report.Rule.AlertingRule == nil && report.Rule.RecordingRule == nil && report.Rule.Error.Err == nil {
+				// problem not related to any rule, like a file parse error
+				name = report.Problem.Reporter
+			}
+			cases[key] = &junitCase{
+				path: report.Path,
+				line: report.Problem.Lines[0],
+				name: name,
+			}
+		}
+		cases[key].problems = append(cases[key].problems, report.Problem)
+	}
+
+	sorted := make([]*junitCase, 0, len(cases))
+	for _, jc := range cases {
+		sorted = append(sorted, jc)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].path != sorted[j].path {
+			return sorted[i].path < sorted[j].path
+		}
+		if sorted[i].line != sorted[j].line {
+			return sorted[i].line < sorted[j].line
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	suites := JUnitTestSuites{Name: "pint"}
+	for _, jc := range sorted {
+		if len(suites.Suites) == 0 || suites.Suites[len(suites.Suites)-1].Name != jc.path {
+			suites.Suites = append(suites.Suites, JUnitTestSuite{Name: jc.path})
+		}
+		suite := &suites.Suites[len(suites.Suites)-1]
+
+		tc := JUnitTestCase{Name: jc.name, Classname: jc.path}
+		var failures, output []string
+		for _, problem := range jc.problems {
+			firstLine, lastLine := problem.LineRange()
+			msg := fmt.Sprintf("%s:%s: %s (%s)", jc.path, printLineRange(firstLine, lastLine), problem.Text, problem.Reporter)
+			if problem.Severity >= checks.Bug {
+				failures = append(failures, msg)
+			} else {
+				output = append(output, msg)
+			}
+		}
+		if len(failures) > 0 {
+			tc.Failure = &JUnitFailure{
+				Message:  fmt.Sprintf("%d problem(s) found", len(failures)),
+				Type:     "pint",
+				Contents: strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+			suites.Failures++
+		}
+		tc.SystemOut = strings.Join(output, "\n")
+
+		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests++
+		suites.Tests++
+	}
+
+	return writeXML(jr.output, suites)
+}
+
+func junitKey(path string, rule parser.Rule) string {
+	return fmt.Sprintf("%s\x00%s\x00%d", path, rule.Name(), firstLine(rule.Lines()))
+}
+
+func junitCaseName(rule parser.Rule) string {
+	if name := rule.Name(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("line %d", firstLine(rule.Lines()))
+}
+
+func firstLine(lines []int) (line int) {
+	for _, l := range lines {
+		if line == 0 || l < line {
+			line = l
+		}
+	}
+	return
+}
@@ -0,0 +1,123 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJUnitReporter(t *testing.T) {
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		output      string
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- alert: errors
+  expr: sum(errors) by (job) > 0
+- alert: passing
+  expr: up == 0
+`))
+
+	testCases := []testCaseT{
+		{
+			description: "no rules",
+			summary:     reporter.Summary{},
+			output: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="pint" tests="0" failures="0"></testsuites>
+`,
+		},
+		{
+			description: "passing and failing rules",
+			summary: reporter.Summary{
+				Entries: []reporter.Entry{
+					{Path: "foo.txt", Rule: mockRules[2]},
+					{Path: "foo.txt", Rule: mockRules[1]},
+					{Path: "foo.txt", Rule: mockRules[0]},
+				},
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{4, 5},
+							Reporter: "mock",
+							Text:     "mock warning",
+							Severity: checks.Warning,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{2, 3},
+							Reporter: "mock",
+							Text:     "mock bug",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{3},
+							Reporter: "mock",
+							Text:     "mock fatal",
+							Severity: checks.Fatal,
+						},
+					},
+					{
+						Path: "bar.txt",
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "pint/parse",
+							Text:     "did not find expected key",
+							Severity: checks.Fatal,
+						},
+					},
+				},
+			},
+			output: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="pint" tests="4" failures="2">
+  <testsuite name="bar.txt" tests="1" failures="1">
+    <testcase name="pint/parse" classname="bar.txt">
+      <failure message="1 problem(s) found" type="pint">bar.txt:1: did not find expected key (pint/parse)</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="foo.txt" tests="3" failures="1">
+    <testcase name="target is down" classname="foo.txt">
+      <failure message="2 problem(s) found" type="pint">foo.txt:2-3: mock bug (mock)&#xA;foo.txt:3: mock fatal (mock)</failure>
+    </testcase>
+    <testcase name="errors" classname="foo.txt">
+      <system-out>foo.txt:4-5: mock warning (mock)</system-out>
+    </testcase>
+    <testcase name="passing" classname="foo.txt"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			r := reporter.NewJUnitReporter(out)
+			if err := r.Submit(tc.summary); err != nil {
+				t.Errorf("Submit() returned an error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tc.output, out.String()); diff != "" {
+				t.Errorf("Submit() wrote wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
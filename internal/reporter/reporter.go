@@ -1,6 +1,11 @@
 package reporter
 
 import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/parser"
@@ -10,6 +15,10 @@ type Report struct {
 	Path    string
 	Rule    parser.Rule
 	Problem checks.Problem
+	// External reports are caused by changes made somewhere else, like a
+	// recording rule removed from another file, CI reporters will include
+	// them even if none of the problem lines were modified
+	External bool
 }
 
 func (r Report) IsPassing() bool {
@@ -21,9 +30,29 @@ func (r Report) IsPassing() bool {
 	}
 }
 
+// Fingerprint returns a hash identifying this report, it doesn't use line
+// numbers so it won't change when only the position of a rule changes
+func (r Report) Fingerprint() string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join([]string{
+		r.Path,
+		r.Rule.Name(),
+		r.Problem.Reporter,
+		r.Problem.Text,
+	}, "\n"))))
+}
+
+// Entry is a single rule that was checked
+type Entry struct {
+	Path string
+	Rule parser.Rule
+}
+
 type Summary struct {
 	Reports     []Report
 	FileChanges discovery.FileFindResults
+	// Entries is the list of all rules that were checked, including those
+	// without any problems found
+	Entries []Entry
 }
 
 func (s Summary) IsPassing() bool {
@@ -58,3 +87,41 @@ func (s Summary) CountBySeverity() map[checks.Severity]int {
 type Reporter interface {
 	Submit(Summary) error
 }
+
+// sortReports returns a copy of reports sorted by path, line, reporter and text
+func sortReports(reports []Report) []Report {
+	reps := make([]Report, len(reports))
+	copy(reps, reports)
+	sort.Slice(reps, func(i, j int) bool {
+		if reps[i].Path < reps[j].Path {
+			return true
+		}
+		if reps[i].Path > reps[j].Path {
+			return false
+		}
+		if reps[i].Problem.Lines[0] < reps[j].Problem.Lines[0] {
+			return true
+		}
+		if reps[i].Problem.Lines[0] > reps[j].Problem.Lines[0] {
+			return false
+		}
+		if reps[i].Problem.Reporter < reps[j].Problem.Reporter {
+			return true
+		}
+		if reps[i].Problem.Reporter > reps[j].Problem.Reporter {
+			return false
+		}
+		return reps[i].Problem.Text < reps[j].Problem.Text
+	})
+	return reps
+}
+
+func ruleKind(rule parser.Rule) string {
+	if rule.AlertingRule != nil {
+		return "alerting"
+	}
+	if rule.RecordingRule != nil {
+		return "recording"
+	}
+	return ""
+}
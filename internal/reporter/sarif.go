@@ -0,0 +1,168 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifToolURI = "https://github.com/cloudflare/pint"
+	sarifHelpURI = "https://github.com/cloudflare/pint/blob/main/docs/CONFIGURATION.md"
+)
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           SarifRegion           `json:"region"`
+}
+
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type SarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations"`
+}
+
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+func NewSarifReporter(output io.Writer) SarifReporter {
+	return SarifReporter{output: output}
+}
+
+// SarifReporter writes reports using Static Analysis Results Interchange Format
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type SarifReporter struct {
+	output io.Writer
+}
+
+func (sr SarifReporter) Submit(summary Summary) error {
+	reports := sortReports(summary.Reports)
+
+	run := SarifRun{
+		Tool: SarifTool{
+			Driver: SarifDriver{
+				Name:           "pint",
+				InformationURI: sarifToolURI,
+				Rules:          []SarifRule{},
+			},
+		},
+		Results: []SarifResult{},
+	}
+
+	ruleIndex := map[string]int{}
+	for _, name := range sarifRuleNames(reports) {
+		ruleIndex[name] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, SarifRule{
+			ID:               name,
+			ShortDescription: SarifMessage{Text: name},
+			HelpURI:          sarifHelpURI,
+		})
+	}
+
+	for _, report := range reports {
+		firstLine, lastLine := report.Problem.LineRange()
+		run.Results = append(run.Results, SarifResult{
+			RuleID:    report.Problem.Reporter,
+			RuleIndex: ruleIndex[report.Problem.Reporter],
+			Level:     sarifLevel(report.Problem.Severity),
+			Message:   SarifMessage{Text: report.Problem.Text},
+			Locations: []SarifLocation{
+				{
+					PhysicalLocation: SarifPhysicalLocation{
+						ArtifactLocation: SarifArtifactLocation{URI: filepath.ToSlash(report.Path)},
+						Region:           SarifRegion{StartLine: firstLine, EndLine: lastLine},
+					},
+				},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(sr.output)
+	enc.SetIndent("", "  ")
+	return enc.Encode(SarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SarifRun{run},
+	})
+}
+
+// sarifRuleNames returns names of all checks followed by names of any other
+// reporters found in reports, like pint/parse
+func sarifRuleNames(reports []Report) (names []string) {
+	known := map[string]struct{}{}
+	for _, name := range checks.CheckNames {
+		names = append(names, name)
+		known[name] = struct{}{}
+	}
+
+	extra := []string{}
+	for _, report := range reports {
+		if _, ok := known[report.Problem.Reporter]; ok {
+			continue
+		}
+		known[report.Problem.Reporter] = struct{}{}
+		extra = append(extra, report.Problem.Reporter)
+	}
+	sort.Strings(extra)
+
+	return append(names, extra...)
+}
+
+func sarifLevel(s checks.Severity) string {
+	switch s {
+	case checks.Fatal, checks.Bug:
+		return "error"
+	case checks.Warning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,197 @@
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSarifReporter(t *testing.T) {
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		extraRules  []string
+		results     []reporter.SarifResult
+	}
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- alert: errors
+  expr: sum(errors) by (job) > 0
+`))
+
+	location := func(path string, start, end int) []reporter.SarifLocation {
+		return []reporter.SarifLocation{
+			{
+				PhysicalLocation: reporter.SarifPhysicalLocation{
+					ArtifactLocation: reporter.SarifArtifactLocation{URI: path},
+					Region:           reporter.SarifRegion{StartLine: start, EndLine: end},
+				},
+			},
+		}
+	}
+
+	indexOf := func(name string) int {
+		for i, n := range checks.CheckNames {
+			if n == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no reports",
+			summary:     reporter.Summary{},
+			results:     []reporter.SarifResult{},
+		},
+		{
+			description: "severity mapping",
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{4, 5},
+							Reporter: checks.AlertsCheckName,
+							Text:     "would trigger 1 alert(s)",
+							Severity: checks.Information,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{3},
+							Reporter: checks.ByCheckName,
+							Text:     "job label is required",
+							Severity: checks.Warning,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{2, 3},
+							Reporter: checks.SyntaxCheckName,
+							Text:     "syntax error",
+							Severity: checks.Fatal,
+						},
+					},
+					{
+						Path: "bar.txt",
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "pint/parse",
+							Text:     "did not find expected key",
+							Severity: checks.Fatal,
+						},
+					},
+					{
+						Path: "foo.txt",
+						Rule: mockRules[1],
+						Problem: checks.Problem{
+							Lines:    []int{5},
+							Reporter: checks.ValueCheckName,
+							Text:     "using $value in labels",
+							Severity: checks.Bug,
+						},
+					},
+				},
+			},
+			extraRules: []string{"pint/parse"},
+			results: []reporter.SarifResult{
+				{
+					RuleID:    "pint/parse",
+					RuleIndex: len(checks.CheckNames),
+					Level:     "error",
+					Message:   reporter.SarifMessage{Text: "did not find expected key"},
+					Locations: location("bar.txt", 1, 1),
+				},
+				{
+					RuleID:    checks.SyntaxCheckName,
+					RuleIndex: indexOf(checks.SyntaxCheckName),
+					Level:     "error",
+					Message:   reporter.SarifMessage{Text: "syntax error"},
+					Locations: location("foo.txt", 2, 3),
+				},
+				{
+					RuleID:    checks.ByCheckName,
+					RuleIndex: indexOf(checks.ByCheckName),
+					Level:     "warning",
+					Message:   reporter.SarifMessage{Text: "job label is required"},
+					Locations: location("foo.txt", 3, 3),
+				},
+				{
+					RuleID:    checks.AlertsCheckName,
+					RuleIndex: indexOf(checks.AlertsCheckName),
+					Level:     "note",
+					Message:   reporter.SarifMessage{Text: "would trigger 1 alert(s)"},
+					Locations: location("foo.txt", 4, 5),
+				},
+				{
+					RuleID:    checks.ValueCheckName,
+					RuleIndex: indexOf(checks.ValueCheckName),
+					Level:     "error",
+					Message:   reporter.SarifMessage{Text: "using $value in labels"},
+					Locations: location("foo.txt", 5, 5),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			r := reporter.NewSarifReporter(out)
+			if err := r.Submit(tc.summary); err != nil {
+				t.Errorf("Submit() returned an error: %s", err)
+				return
+			}
+
+			var got reporter.SarifLog
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Errorf("failed to decode SARIF output: %s", err)
+				return
+			}
+
+			rules := []reporter.SarifRule{}
+			for _, name := range append(append([]string{}, checks.CheckNames...), tc.extraRules...) {
+				rules = append(rules, reporter.SarifRule{
+					ID:               name,
+					ShortDescription: reporter.SarifMessage{Text: name},
+					HelpURI:          "https://github.com/cloudflare/pint/blob/main/docs/CONFIGURATION.md",
+				})
+			}
+			expected := reporter.SarifLog{
+				Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+				Version: "2.1.0",
+				Runs: []reporter.SarifRun{
+					{
+						Tool: reporter.SarifTool{
+							Driver: reporter.SarifDriver{
+								Name:           "pint",
+								InformationURI: "https://github.com/cloudflare/pint",
+								Rules:          rules,
+							},
+						},
+						Results: tc.results,
+					},
+				},
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("Submit() wrote wrong output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}